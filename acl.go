@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"strings"
@@ -37,9 +38,92 @@ func (c *Client) ACL() *ACL {
 
 // List returns an overview of the API submitted ACLs
 func (a *ACL) List() (*ListACLsResponse, error) {
-	url := a.c.RundeckAddr + "/system/acl/"
+	return a.ListWithContext(context.Background())
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (a *ACL) ListWithContext(ctx context.Context) (*ListACLsResponse, error) {
+	return a.c.listACLs(ctx, a.c.aclURL(SystemACLScope, ""))
+}
+
+// Get retrieves the YAML text of the ACL Policy file.  The contents of the file as a []byte will be returned.
+func (a *ACL) Get(name string) ([]byte, error) {
+	return a.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is the same as Get with the addition of the ability to pass a context.
+func (a *ACL) GetWithContext(ctx context.Context, name string) ([]byte, error) {
+	return a.c.getACL(ctx, a.c.aclURL(SystemACLScope, name))
+}
+
+// Create is used to create an ACL policy.  When Rundeck rejects the policy as invalid the error is an *ACLValidationError.
+func (a *ACL) Create(name string, policy []byte) error {
+	return a.CreateWithContext(context.Background(), name, policy)
+}
+
+// CreateWithContext is the same as Create with the addition of the ability to pass a context.
+func (a *ACL) CreateWithContext(ctx context.Context, name string, policy []byte) error {
+	return a.c.createACL(ctx, a.c.aclURL(SystemACLScope, name), policy)
+}
+
+// Update updates an existing acl policy.  When Rundeck rejects the policy as invalid the error is an *ACLValidationError.
+func (a *ACL) Update(name string, policy []byte) error {
+	return a.UpdateWithContext(context.Background(), name, policy)
+}
+
+// UpdateWithContext is the same as Update with the addition of the ability to pass a context.
+func (a *ACL) UpdateWithContext(ctx context.Context, name string, policy []byte) error {
+	return a.c.updateACL(ctx, a.c.aclURL(SystemACLScope, name), policy)
+}
+
+// Delete removes an ACL polciy file
+func (a *ACL) Delete(name string) error {
+	return a.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is the same as Delete with the addition of the ability to pass a context.
+func (a *ACL) DeleteWithContext(ctx context.Context, name string) error {
+	return a.c.deleteACL(ctx, a.c.aclURL(SystemACLScope, name))
+}
 
-	res, err := a.c.checkResponseOK(a.c.get(url))
+// ACLScope is where ACL policies are stored, either the system or a project
+type ACLScope struct {
+	// Project is empty for the system scope
+	Project string
+}
+
+// SystemACLScope is the scope of the policies managed by ACL
+var SystemACLScope = ACLScope{}
+
+// ProjectACLScope is the scope of the policies stored in a project
+func ProjectACLScope(project string) ACLScope {
+	return ACLScope{Project: project}
+}
+
+// String names the scope
+func (s ACLScope) String() string {
+	if s.Project == "" {
+		return "system"
+	}
+	return "project " + s.Project
+}
+
+// aclURL returns the URL of a policy in a scope, or of the scope's listing when name is empty
+func (c *Client) aclURL(scope ACLScope, name string) string {
+	url := c.RundeckAddr + "/system/acl/"
+	if scope.Project != "" {
+		url = c.RundeckAddr + "/project/" + scope.Project + "/acl/"
+	}
+	if name == "" {
+		return url
+	}
+	return url + sanitizeACLName(name)
+}
+
+// listACLs, getACL, createACL, updateACL and deleteACL are shared by the system and project ACL endpoints
+
+func (c *Client) listACLs(ctx context.Context, url string) (*ListACLsResponse, error) {
+	res, err := c.checkResponseOK(c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -49,11 +133,8 @@ func (a *ACL) List() (*ListACLsResponse, error) {
 	return &listACLs, json.NewDecoder(res.Body).Decode(&listACLs)
 }
 
-// Get retrieves the YAML text of the ACL Policy file.  The contents of the file as a []byte will be returned.
-func (a *ACL) Get(name string) ([]byte, error) {
-	url := a.c.RundeckAddr + "/system/acl/" + a.sanitizeACLName(name)
-
-	res, err := a.c.checkResponseOK(a.c.getWithAdditionalHeaders(url, map[string]string{"Accept": "text/plain"}))
+func (c *Client) getACL(ctx context.Context, url string) ([]byte, error) {
+	res, err := c.checkResponseOK(c.getWithAdditionalHeaders(ctx, url, map[string]string{"Accept": "text/plain"}))
 	if err != nil {
 		return nil, err
 	}
@@ -62,37 +143,28 @@ func (a *ACL) Get(name string) ([]byte, error) {
 	return ioutil.ReadAll(res.Body)
 }
 
-// Create is used to create an ACL policy
-func (a *ACL) Create(name string, policy []byte) error {
-	url := a.c.RundeckAddr + "/system/acl/" + a.sanitizeACLName(name)
-
-	res, err := a.c.checkResponseCreated(a.c.postWithAdditionalHeaders(url, map[string]string{"Content-Type": "text/plain"}, bytes.NewReader(policy)))
+func (c *Client) createACL(ctx context.Context, url string, policy []byte) error {
+	res, err := c.checkResponseCreated(c.postWithAdditionalHeaders(ctx, url, map[string]string{"Content-Type": "text/plain"}, bytes.NewReader(policy)))
 	if err != nil {
-		return err
+		return aclValidationError(err)
 	}
 	defer res.Body.Close()
 
 	return nil
 }
 
-// Update updates an existing acl policy
-func (a *ACL) Update(name string, policy []byte) error {
-	url := a.c.RundeckAddr + "/system/acl/" + a.sanitizeACLName(name)
-
-	res, err := a.c.checkResponseOK(a.c.putWithAdditionalHeaders(url, map[string]string{"Content-Type": "text/plain"}, bytes.NewReader(policy)))
+func (c *Client) updateACL(ctx context.Context, url string, policy []byte) error {
+	res, err := c.checkResponseOK(c.putWithAdditionalHeaders(ctx, url, map[string]string{"Content-Type": "text/plain"}, bytes.NewReader(policy)))
 	if err != nil {
-		return err
+		return aclValidationError(err)
 	}
 	defer res.Body.Close()
 
 	return nil
 }
 
-// Delete removes an ACL polciy file
-func (a *ACL) Delete(name string) error {
-	url := a.c.RundeckAddr + "/system/acl/" + a.sanitizeACLName(name)
-
-	res, err := a.c.checkResponseNoContent(a.c.delete(url, nil))
+func (c *Client) deleteACL(ctx context.Context, url string) error {
+	res, err := c.checkResponseNoContent(c.delete(ctx, url, nil))
 	if err != nil {
 		return err
 	}
@@ -101,7 +173,7 @@ func (a *ACL) Delete(name string) error {
 	return nil
 }
 
-func (a *ACL) sanitizeACLName(name string) string {
+func sanitizeACLName(name string) string {
 	if !strings.HasSuffix(name, aclPolicySuffix) {
 		name += aclPolicySuffix
 	}
@@ -0,0 +1,408 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ACLDecision is the outcome of evaluating an ACL request
+type ACLDecision string
+
+const (
+	// ACLDecisionAllowed means a rule allows the action and none deny it
+	ACLDecisionAllowed ACLDecision = "allowed"
+	// ACLDecisionDenied means a rule denies the action, which overrides any rule allowing it
+	ACLDecisionDenied ACLDecision = "denied"
+	// ACLDecisionRejected means no rule mentions the action, so it is not allowed
+	ACLDecisionRejected ACLDecision = "rejected"
+)
+
+// ACLSubject is the user a question is asked about
+type ACLSubject struct {
+	Username string
+	Groups   []string
+	URNs     []string
+}
+
+// ACLRequest asks whether a subject may perform an action on a resource
+type ACLRequest struct {
+	Subject ACLSubject
+	// Project selects the project context.  When it is empty the application context is used.
+	Project string
+	Type    ACLResourceType
+	// Resource are the properties the rule selectors are matched against, e.g. name and group for jobs
+	Resource map[string]string
+	Action   string
+}
+
+// JobACLRequest asks whether a subject may perform an action on a job
+func JobACLRequest(subject ACLSubject, project, group, name, action string) *ACLRequest {
+	return &ACLRequest{
+		Subject:  subject,
+		Project:  project,
+		Type:     ACLResourceTypeJob,
+		Resource: map[string]string{"group": group, "name": name},
+		Action:   action,
+	}
+}
+
+// NodeACLRequest asks whether a subject may perform an action on a node.  Attributes are the node's
+// attributes, with tags as a comma separated list.
+func NodeACLRequest(subject ACLSubject, project string, attributes map[string]string, action string) *ACLRequest {
+	return &ACLRequest{
+		Subject:  subject,
+		Project:  project,
+		Type:     ACLResourceTypeNode,
+		Resource: attributes,
+		Action:   action,
+	}
+}
+
+// StorageACLRequest asks whether a subject may perform an action on a key storage path.  An empty
+// project asks about the application context.
+func StorageACLRequest(subject ACLSubject, project, keyPath, action string) *ACLRequest {
+	keyPath = "keys/" + keyRelativePath(keyPath)
+	return &ACLRequest{
+		Subject:  subject,
+		Project:  project,
+		Type:     ACLResourceTypeStorage,
+		Resource: map[string]string{"path": keyPath, "name": path.Base(keyPath)},
+		Action:   action,
+	}
+}
+
+// ProjectACLRequest asks whether a subject may perform an action on a project, in the application context
+func ProjectACLRequest(subject ACLSubject, project, action string) *ACLRequest {
+	return &ACLRequest{
+		Subject:  subject,
+		Type:     ACLResourceTypeProject,
+		Resource: map[string]string{"name": project},
+		Action:   action,
+	}
+}
+
+// ACLRuleMatch is a rule that applied to a request
+type ACLRuleMatch struct {
+	// Source is the name the policy was added under, usually its file name
+	Source string
+	Policy *ACLPolicy
+	// Document and Index locate the rule: the policy's position in its file, counted from 1 like ACLLintIssue,
+	// and the rule's position in its section, counted from 0 like the rule paths of ACLLintIssue
+	Document int
+	Index    int
+	Rule     *ACLRule
+	// Effect is allowed or denied
+	Effect ACLDecision
+}
+
+// String describes the rule, e.g. ops.aclpolicy[1] rule 1: deny [*]
+func (m *ACLRuleMatch) String() string {
+	actions := m.Rule.Allow
+	verb := "allow"
+	if m.Effect == ACLDecisionDenied {
+		actions = m.Rule.Deny
+		verb = "deny"
+	}
+	description := ""
+	if m.Policy.Description != "" {
+		description = fmt.Sprintf(" (%s)", m.Policy.Description)
+	}
+	return fmt.Sprintf("%s[%d]%s rule %d: %s [%s]", m.Source, m.Document, description, m.Index, verb, strings.Join(actions, ", "))
+}
+
+// ACLEvaluation is the answer to an ACL request, along with the rules that led to it
+type ACLEvaluation struct {
+	Request  *ACLRequest
+	Decision ACLDecision
+	// Deciding is the rule the decision comes from, nil when the request was rejected
+	Deciding *ACLRuleMatch
+	// Matches are all the rules that allowed or denied the action
+	Matches []*ACLRuleMatch
+}
+
+// Allowed reports whether the action is allowed
+func (e *ACLEvaluation) Allowed() bool {
+	return e.Decision == ACLDecisionAllowed
+}
+
+// String explains the decision
+func (e *ACLEvaluation) String() string {
+	var b strings.Builder
+	r := e.Request
+	scope := "application"
+	if r.Project != "" {
+		scope = "project " + r.Project
+	}
+	fmt.Fprintf(&b, "%s: %s %s %s %s in %s\n", strings.ToUpper(string(e.Decision)), r.Subject, r.Action, r.Type, formatACLResource(r.Resource), scope)
+	if e.Deciding == nil {
+		b.WriteString("  no rule allows or denies the action\n")
+	}
+	for _, match := range e.Matches {
+		marker := " "
+		if match == e.Deciding {
+			marker = "*"
+		}
+		fmt.Fprintf(&b, " %s %s\n", marker, match)
+	}
+	return b.String()
+}
+
+// String renders the subject's username and groups
+func (s ACLSubject) String() string {
+	subject := s.Username
+	if len(s.Groups) > 0 {
+		subject += " (" + strings.Join(s.Groups, ", ") + ")"
+	}
+	return subject
+}
+
+func formatACLResource(resource map[string]string) string {
+	var pairs []string
+	for _, key := range sortedStringKeys(resource) {
+		pairs = append(pairs, key+"="+resource[key])
+	}
+	return "{" + strings.Join(pairs, " ") + "}"
+}
+
+type aclSource struct {
+	name     string
+	policies []*ACLPolicy
+}
+
+// ACLEvaluator answers ACL requests offline, using Rundeck's deny-overrides semantics: an action is
+// allowed when a rule allows it and no rule denies it.  It is safe for concurrent use once every
+// policy has been added.
+type ACLEvaluator struct {
+	sources []*aclSource
+	regexps map[string]*regexp.Regexp
+}
+
+// NewACLEvaluator returns an evaluator without any policies
+func NewACLEvaluator() *ACLEvaluator {
+	return &ACLEvaluator{regexps: make(map[string]*regexp.Regexp)}
+}
+
+// Add adds the policies of a file.  Source names the file in explanations.  An error is returned
+// when a policy holds an invalid regular expression.
+func (e *ACLEvaluator) Add(source string, policies []*ACLPolicy) error {
+	for i, policy := range policies {
+		patterns := []string{policy.Context.Project}
+		for _, subjects := range []*ACLSubjects{policy.By, policy.NotBy} {
+			if subjects != nil {
+				patterns = append(patterns, subjects.Group...)
+				patterns = append(patterns, subjects.Username...)
+				patterns = append(patterns, subjects.URN...)
+			}
+		}
+		for _, resourceType := range aclResourceTypes {
+			for _, rule := range policy.For.Rules(resourceType) {
+				for _, pattern := range rule.Match {
+					patterns = append(patterns, pattern)
+				}
+			}
+		}
+
+		for _, pattern := range patterns {
+			if _, ok := e.regexps[pattern]; ok {
+				continue
+			}
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return fmt.Errorf("%s[%d]: %v", source, i+1, err)
+			}
+			e.regexps[pattern] = re
+		}
+	}
+
+	e.sources = append(e.sources, &aclSource{name: source, policies: policies})
+	return nil
+}
+
+var aclResourceTypes = []ACLResourceType{
+	ACLResourceTypeResource,
+	ACLResourceTypeAdhoc,
+	ACLResourceTypeJob,
+	ACLResourceTypeNode,
+	ACLResourceTypeProject,
+	ACLResourceTypeProjectACL,
+	ACLResourceTypeStorage,
+}
+
+// Evaluate answers an ACL request
+func (e *ACLEvaluator) Evaluate(request *ACLRequest) *ACLEvaluation {
+	evaluation := &ACLEvaluation{Request: request, Decision: ACLDecisionRejected}
+
+	for _, source := range e.sources {
+		for i, policy := range source.policies {
+			if !e.appliesTo(policy, request) {
+				continue
+			}
+			for j, rule := range policy.For.Rules(request.Type) {
+				if !e.ruleMatches(rule, request.Resource) {
+					continue
+				}
+
+				var effect ACLDecision
+				switch {
+				case aclActionListed(rule.Deny, request.Action):
+					effect = ACLDecisionDenied
+				case aclActionListed(rule.Allow, request.Action):
+					effect = ACLDecisionAllowed
+				default:
+					continue
+				}
+
+				match := &ACLRuleMatch{Source: source.name, Policy: policy, Document: i + 1, Index: j, Rule: rule, Effect: effect}
+				evaluation.Matches = append(evaluation.Matches, match)
+
+				// the first deny decides, otherwise the first allow
+				if effect == ACLDecisionDenied && evaluation.Decision != ACLDecisionDenied {
+					evaluation.Decision = ACLDecisionDenied
+					evaluation.Deciding = match
+				} else if effect == ACLDecisionAllowed && evaluation.Decision == ACLDecisionRejected {
+					evaluation.Decision = ACLDecisionAllowed
+					evaluation.Deciding = match
+				}
+			}
+		}
+	}
+
+	return evaluation
+}
+
+func (e *ACLEvaluator) matches(pattern, value string) bool {
+	re, ok := e.regexps[pattern]
+	return ok && re.MatchString(value)
+}
+
+func (e *ACLEvaluator) matchesAny(patterns []string, values []string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if e.matches(pattern, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *ACLEvaluator) appliesTo(policy *ACLPolicy, request *ACLRequest) bool {
+	if request.Project != "" {
+		if policy.Context.Project == "" || !e.matches(policy.Context.Project, request.Project) {
+			return false
+		}
+	} else if policy.Context.Application == "" {
+		return false
+	}
+
+	// by names who the policy applies to and notBy who it leaves out, a policy with only notBy
+	// applying to everyone else
+	if policy.By == nil && policy.NotBy == nil {
+		return false
+	}
+	if policy.By != nil && !e.subjectMatches(policy.By, request.Subject) {
+		return false
+	}
+	return policy.NotBy == nil || !e.subjectMatches(policy.NotBy, request.Subject)
+}
+
+func (e *ACLEvaluator) subjectMatches(subjects *ACLSubjects, subject ACLSubject) bool {
+	return e.matchesAny(subjects.Username, []string{subject.Username}) ||
+		e.matchesAny(subjects.Group, subject.Groups) ||
+		e.matchesAny(subjects.URN, subject.URNs)
+}
+
+// ruleMatches reports whether every selector of the rule matches the resource
+func (e *ACLEvaluator) ruleMatches(rule *ACLRule, resource map[string]string) bool {
+	for key, pattern := range rule.Match {
+		value, ok := resource[key]
+		if !ok || !e.matches(pattern, value) {
+			return false
+		}
+	}
+	for key, expected := range rule.Equals {
+		value, ok := resource[key]
+		if !ok || value != expected {
+			return false
+		}
+	}
+	for key, expected := range rule.Contains {
+		value, ok := resource[key]
+		if !ok {
+			return false
+		}
+		present := aclValueSet(value)
+		for _, item := range expected {
+			if !present[item] {
+				return false
+			}
+		}
+	}
+	for key, allowed := range rule.Subset {
+		value, ok := resource[key]
+		if !ok {
+			return false
+		}
+		for item := range aclValueSet(value) {
+			if !aclStringListed(allowed, item) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// aclValueSet splits a comma separated resource value, e.g. node tags, into its items
+func aclValueSet(value string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
+
+func aclStringListed(values ACLStrings, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func aclActionListed(actions ACLStrings, action string) bool {
+	for _, a := range actions {
+		if a == "*" || a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluator loads every system ACL policy from the server into an evaluator
+func (a *ACL) Evaluator(ctx context.Context) (*ACLEvaluator, error) {
+	list, err := a.ListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator := NewACLEvaluator()
+	for _, resource := range list.Resources {
+		if resource.Type != "file" {
+			continue
+		}
+		policies, err := a.GetPoliciesWithContext(ctx, resource.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := evaluator.Add(resource.Name, policies); err != nil {
+			return nil, err
+		}
+	}
+	return evaluator, nil
+}
@@ -0,0 +1,156 @@
+package rundeck_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func TestACLEvaluator(t *testing.T) {
+	policies, err := rundeck.ParseACLPolicies([]byte(testACLPolicy))
+	if err != nil {
+		t.Fatal("failed to parse policies", err)
+	}
+	evaluator := rundeck.NewACLEvaluator()
+	if err := evaluator.Add("ops.aclpolicy", policies); err != nil {
+		t.Fatal("failed to add policies", err)
+	}
+
+	ops := rundeck.ACLSubject{Username: "carol", Groups: []string{"users", "ops"}}
+	dev := rundeck.ACLSubject{Username: "dave", Groups: []string{"dev"}}
+	alice := rundeck.ACLSubject{Username: "alice"}
+
+	tests := []struct {
+		name     string
+		request  *rundeck.ACLRequest
+		decision rundeck.ACLDecision
+	}{
+		{"allowed job", rundeck.JobACLRequest(ops, "ops-1", "deploy/web", "release", "run"), rundeck.ACLDecisionAllowed},
+		{"unlisted action", rundeck.JobACLRequest(ops, "ops-1", "deploy/web", "release", "kill"), rundeck.ACLDecisionRejected},
+		{"other project", rundeck.JobACLRequest(ops, "dev", "deploy/web", "release", "run"), rundeck.ACLDecisionRejected},
+		{"other group", rundeck.JobACLRequest(dev, "ops-1", "deploy/web", "release", "run"), rundeck.ACLDecisionRejected},
+		{"deny overrides", rundeck.JobACLRequest(ops, "ops-1", "deploy/db", "drop database", "run"), rundeck.ACLDecisionDenied},
+		{"node tags", rundeck.NodeACLRequest(ops, "ops-1", map[string]string{"nodename": "db1", "tags": "db, prod, east"}, "read"), rundeck.ACLDecisionAllowed},
+		{"missing tag", rundeck.NodeACLRequest(ops, "ops-1", map[string]string{"nodename": "web1", "tags": "prod"}, "read"), rundeck.ACLDecisionRejected},
+		{"key path", rundeck.StorageACLRequest(ops, "ops-1", "ops/db/password", "read"), rundeck.ACLDecisionAllowed},
+		{"other key path", rundeck.StorageACLRequest(ops, "ops-1", "keys/dev/password", "read"), rundeck.ACLDecisionRejected},
+		{"project by username", rundeck.ProjectACLRequest(alice, "ops-1", "read"), rundeck.ACLDecisionAllowed},
+		{"project by urn", rundeck.ProjectACLRequest(rundeck.ACLSubject{Username: "eve", URNs: []string{"urn:ldap:eve"}}, "ops-2", "read"), rundeck.ACLDecisionAllowed},
+		{"project outside pattern", rundeck.ProjectACLRequest(alice, "dev", "read"), rundeck.ACLDecisionRejected},
+	}
+
+	for _, test := range tests {
+		evaluation := evaluator.Evaluate(test.request)
+		if evaluation.Decision != test.decision {
+			t.Errorf("%s: expected %s\n%s", test.name, test.decision, evaluation)
+		}
+	}
+
+	explained := evaluator.Evaluate(rundeck.JobACLRequest(ops, "ops-1", "deploy/db", "drop database", "run"))
+	if explained.Allowed() || len(explained.Matches) != 2 || explained.Deciding.Effect != rundeck.ACLDecisionDenied {
+		t.Fatalf("unexpected evaluation:\n%s", explained)
+	}
+	report := explained.String()
+	for _, line := range []string{
+		"DENIED: carol (users, ops) run job {group=deploy/db name=drop database} in project ops-1",
+		"* ops.aclpolicy[1] (Ops can run jobs) rule 1: deny [*]",
+		"  ops.aclpolicy[1] (Ops can run jobs) rule 0: allow [run, read]",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("expected %q in the explanation:\n%s", line, report)
+		}
+	}
+
+	invalid := []*rundeck.ACLPolicy{{Context: rundeck.ACLContext{Project: "("}, By: &rundeck.ACLSubjects{Group: rundeck.ACLStrings{"ops"}}}}
+	if err := rundeck.NewACLEvaluator().Add("invalid.aclpolicy", invalid); err == nil || !strings.HasPrefix(err.Error(), "invalid.aclpolicy[1]:") {
+		t.Errorf("expected the invalid regular expression to be located like the linter does, received %v\n", err)
+	}
+}
+
+func TestACLEvaluatorSelectors(t *testing.T) {
+	policies, err := rundeck.ParseACLPolicies([]byte(`context:
+  project: ops
+for:
+  job:
+    - subset:
+        tags: [prod, web]
+      allow: run
+  node:
+    - allow: read
+by:
+  group: ops
+notBy:
+  username: mallory
+---
+context:
+  project: ops
+for:
+  node:
+    - deny: run
+notBy:
+  group: ops
+`))
+	if err != nil {
+		t.Fatal("failed to parse policies", err)
+	}
+	evaluator := rundeck.NewACLEvaluator()
+	if err := evaluator.Add("selectors.aclpolicy", policies); err != nil {
+		t.Fatal("failed to add policies", err)
+	}
+
+	carol := rundeck.ACLSubject{Username: "carol", Groups: []string{"ops"}}
+	mallory := rundeck.ACLSubject{Username: "mallory", Groups: []string{"ops"}}
+	dave := rundeck.ACLSubject{Username: "dave", Groups: []string{"dev"}}
+	prodJob := rundeck.JobACLRequest(carol, "ops", "", "release", "run")
+	prodJob.Resource["tags"] = "prod"
+
+	tests := []struct {
+		name     string
+		request  *rundeck.ACLRequest
+		decision rundeck.ACLDecision
+	}{
+		{"subset", prodJob, rundeck.ACLDecisionAllowed},
+		{"untagged job", rundeck.JobACLRequest(carol, "ops", "", "anything", "run"), rundeck.ACLDecisionRejected},
+		{"rule without selectors", rundeck.NodeACLRequest(carol, "ops", map[string]string{"tags": "prod, db"}, "read"), rundeck.ACLDecisionAllowed},
+		{"excluded by notBy", rundeck.NodeACLRequest(mallory, "ops", map[string]string{"tags": "prod"}, "read"), rundeck.ACLDecisionRejected},
+		{"notBy only", rundeck.NodeACLRequest(dave, "ops", map[string]string{"tags": "prod"}, "run"), rundeck.ACLDecisionDenied},
+		{"notBy only excludes", rundeck.NodeACLRequest(carol, "ops", map[string]string{"tags": "prod"}, "run"), rundeck.ACLDecisionRejected},
+	}
+	for _, test := range tests {
+		evaluation := evaluator.Evaluate(test.request)
+		if evaluation.Decision != test.decision {
+			t.Errorf("%s: expected %s\n%s", test.name, test.decision, evaluation)
+		}
+	}
+
+	mallorysJob := rundeck.JobACLRequest(mallory, "ops", "", "release", "run")
+	mallorysJob.Resource["tags"] = "prod"
+	if evaluation := evaluator.Evaluate(mallorysJob); evaluation.Allowed() {
+		t.Errorf("expected notBy to exclude mallory\n%s", evaluation)
+	}
+	webJob := rundeck.JobACLRequest(carol, "ops", "", "release", "run")
+	webJob.Resource["tags"] = "web,db"
+	if evaluation := evaluator.Evaluate(webJob); evaluation.Allowed() {
+		t.Errorf("expected a tag outside the subset to reject the job\n%s", evaluation)
+	}
+}
+
+func TestACLEvaluatorFromServer(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+
+	if err := cli.ACL().Create("evaluator", []byte(testACLPolicy)); err != nil {
+		t.Fatal("failed to create policy", err)
+	}
+	defer cli.ACL().Delete("evaluator")
+
+	evaluator, err := cli.ACL().Evaluator(context.Background())
+	if err != nil {
+		t.Fatal("failed to load policies", err)
+	}
+	evaluation := evaluator.Evaluate(rundeck.JobACLRequest(rundeck.ACLSubject{Username: "carol", Groups: []string{"ops"}}, "ops-1", "deploy/web", "release", "read"))
+	if !evaluation.Allowed() || evaluation.Deciding.Source != "evaluator.aclpolicy" {
+		t.Errorf("unexpected evaluation:\n%s", evaluation)
+	}
+}
@@ -0,0 +1,452 @@
+package rundeck
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ACLPolicyValidation holds the problems Rundeck found in one document of a policy file
+type ACLPolicyValidation struct {
+	// Policy locates the document, e.g. ops.aclpolicy[1], counting from one
+	Policy string   `json:"policy"`
+	Errors []string `json:"errors"`
+}
+
+// ACLValidationError is returned by ACL.Create and ACL.Update when Rundeck rejects a policy as invalid.
+// It unwraps to the underlying APIError.
+type ACLValidationError struct {
+	Err      *APIError
+	Policies []*ACLPolicyValidation
+}
+
+// Error lists every validation message
+func (e *ACLValidationError) Error() string {
+	var messages []string
+	for _, policy := range e.Policies {
+		for _, message := range policy.Errors {
+			messages = append(messages, policy.Policy+": "+message)
+		}
+	}
+	return "rundeck: invalid ACL policy: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the APIError, so errors.Is and errors.As keep working
+func (e *ACLValidationError) Unwrap() error {
+	return e.Err
+}
+
+// aclValidationError turns the validation response of a rejected policy into an ACLValidationError,
+// returning any other error unchanged
+func aclValidationError(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+
+	var validation struct {
+		Valid    bool                   `json:"valid"`
+		Policies []*ACLPolicyValidation `json:"policies"`
+	}
+	if json.Unmarshal([]byte(apiErr.Body), &validation) != nil || validation.Valid || len(validation.Policies) == 0 {
+		return err
+	}
+	return &ACLValidationError{Err: apiErr, Policies: validation.Policies}
+}
+
+// ACLLintSeverity is how serious a lint issue is
+type ACLLintSeverity string
+
+const (
+	// ACLLintError is a problem Rundeck rejects the policy for
+	ACLLintError ACLLintSeverity = "error"
+	// ACLLintWarning is accepted by Rundeck but is most likely a mistake
+	ACLLintWarning ACLLintSeverity = "warning"
+)
+
+// ACLLintIssue is a single problem found by LintACLPolicies
+type ACLLintIssue struct {
+	Severity ACLLintSeverity
+	// Policy locates the document the same way Rundeck does, e.g. ops.aclpolicy[1]
+	Policy string
+	// Path locates the problem within the document, e.g. for.job[0].allow
+	Path    string
+	Message string
+}
+
+// String renders the issue on a single line
+func (i *ACLLintIssue) String() string {
+	location := i.Policy
+	if i.Path != "" {
+		location += " " + i.Path
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, location, i.Message)
+}
+
+// ACLLintErrors reports whether any of the issues is an error
+func ACLLintErrors(issues []*ACLLintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == ACLLintError {
+			return true
+		}
+	}
+	return false
+}
+
+// aclActions are the actions Rundeck knows for each resource type
+var aclActions = map[ACLResourceType][]string{
+	ACLResourceTypeResource: {
+		"read", "create", "update", "delete", "admin", "app_admin", "ops_admin", "refresh", "configure",
+		"enable_executions", "disable_executions", "generate_user_token", "generate_service_token",
+	},
+	ACLResourceTypeAdhoc: {"read", "run", "runAs", "kill", "killAs"},
+	ACLResourceTypeJob: {
+		"read", "view", "view_history", "update", "delete", "create", "run", "runAs", "kill", "killAs",
+		"toggle_schedule", "toggle_execution", "scm_create", "scm_update", "scm_delete",
+	},
+	ACLResourceTypeNode:       {"read", "run"},
+	ACLResourceTypeProject:    {"read", "configure", "delete", "import", "export", "delete_execution", "promote", "admin", "app_admin", "ops_admin"},
+	ACLResourceTypeProjectACL: {"read", "create", "update", "delete", "admin", "app_admin"},
+	ACLResourceTypeStorage:    {"read", "create", "update", "delete"},
+}
+
+// aclProjectTypes and aclApplicationTypes are the resource types each context has rules for
+var (
+	aclProjectTypes     = []string{"resource", "adhoc", "job", "node", "storage"}
+	aclApplicationTypes = []string{"resource", "project", "project_acl", "storage"}
+)
+
+// LintACLPolicies checks the documents of a policy file for mistakes before it is uploaded.  Name is
+// the file name used to locate issues.  Errors are problems Rundeck would reject the policy for, such as
+// a missing by section or a typo in the context, warnings are unknown actions and allow rules that a
+// deny rule always overrides.
+func LintACLPolicies(name string, content []byte) []*ACLLintIssue {
+	return lintACLPolicies(name, content, false)
+}
+
+// LintProjectACLPolicies is the same as LintACLPolicies for a policy stored in a project, where the
+// context is implied by the project and must be left out
+func LintProjectACLPolicies(name string, content []byte) []*ACLLintIssue {
+	return lintACLPolicies(name, content, true)
+}
+
+func lintACLPolicies(name string, content []byte, project bool) []*ACLLintIssue {
+	var issues []*ACLLintIssue
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for index := 1; ; {
+		var document interface{}
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		l := &aclLinter{policy: fmt.Sprintf("%s[%d]", name, index), project: project}
+		if err != nil {
+			l.report(ACLLintError, "", "invalid YAML: %v", err)
+			issues = append(issues, l.issues...)
+			break
+		}
+		if document == nil {
+			continue
+		}
+		index++
+
+		l.lint(document)
+		issues = append(issues, l.issues...)
+	}
+	return issues
+}
+
+type aclLinter struct {
+	policy  string
+	project bool
+	issues  []*ACLLintIssue
+}
+
+func (l *aclLinter) report(severity ACLLintSeverity, path, format string, args ...interface{}) {
+	l.issues = append(l.issues, &ACLLintIssue{
+		Severity: severity,
+		Policy:   l.policy,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// section checks the keys of a map against the known ones, returning the map
+func (l *aclLinter) section(path string, value interface{}, known []string) map[string]interface{} {
+	raw, ok := value.(map[interface{}]interface{})
+	if !ok {
+		l.report(ACLLintError, path, "must be a map")
+		return nil
+	}
+
+	section := make(map[string]interface{}, len(raw))
+	for key, v := range raw {
+		section[fmt.Sprint(key)] = v
+	}
+	for _, key := range sortedKeys(section) {
+		if contains(known, key) {
+			continue
+		}
+		location := key
+		if path != "" {
+			location = path + "." + key
+		}
+		if suggestion := closestName(key, known); suggestion != "" {
+			l.report(ACLLintError, location, "unknown key %q, did you mean %q?", key, suggestion)
+		} else {
+			l.report(ACLLintError, location, "unknown key %q", key)
+		}
+	}
+	return section
+}
+
+func (l *aclLinter) lint(document interface{}) {
+	top := l.section("", document, []string{"description", "context", "for", "by", "notBy"})
+	if top == nil {
+		return
+	}
+
+	if context, ok := top["context"]; l.project {
+		if ok {
+			l.report(ACLLintError, "context", "context is implied by the project and must be left out")
+		}
+	} else if !ok {
+		l.report(ACLLintError, "context", "context section is required")
+	} else if section := l.section("context", context, []string{"project", "application"}); section != nil {
+		_, project := section["project"]
+		_, application := section["application"]
+		if project == application {
+			l.report(ACLLintError, "context", "context must have exactly one of project and application")
+		}
+	}
+
+	by, hasBy := top["by"]
+	notBy, hasNotBy := top["notBy"]
+	if !hasBy && !hasNotBy {
+		l.report(ACLLintError, "by", "by section is required")
+	}
+	if hasBy {
+		l.lintSubjects("by", by)
+	}
+	if hasNotBy {
+		l.lintSubjects("notBy", notBy)
+	}
+
+	if rules, ok := top["for"]; !ok {
+		l.report(ACLLintError, "for", "for section is required")
+	} else if section := l.section("for", rules, append(append([]string{}, aclProjectTypes...), "project", "project_acl")); section != nil {
+		if len(section) == 0 {
+			l.report(ACLLintError, "for", "for section must have rules")
+		}
+		for _, resourceType := range sortedKeys(section) {
+			l.lintRules("for."+resourceType, section[resourceType])
+		}
+	}
+
+	if len(l.issues) > 0 {
+		return
+	}
+
+	// the structure is sound, check what it says
+	bs, err := yaml.Marshal(document)
+	if err != nil {
+		l.report(ACLLintError, "", "%v", err)
+		return
+	}
+	var policy ACLPolicy
+	if err := yaml.Unmarshal(bs, &policy); err != nil {
+		l.report(ACLLintError, "", "%v", err)
+		return
+	}
+	l.lintPolicy(&policy)
+}
+
+func (l *aclLinter) lintSubjects(path string, value interface{}) {
+	if section := l.section(path, value, []string{"group", "username", "urn"}); section != nil && len(section) == 0 {
+		l.report(ACLLintError, path, "%s section must name a group, username or urn", path)
+	}
+}
+
+func (l *aclLinter) lintRules(path string, value interface{}) {
+	rules, ok := value.([]interface{})
+	if !ok {
+		l.report(ACLLintError, path, "must be a list of rules")
+		return
+	}
+	for i, rule := range rules {
+		rulePath := fmt.Sprintf("%s[%d]", path, i)
+		section := l.section(rulePath, rule, []string{"match", "equals", "contains", "subset", "allow", "deny"})
+		if section == nil {
+			continue
+		}
+		_, allow := section["allow"]
+		_, deny := section["deny"]
+		if !allow && !deny {
+			l.report(ACLLintError, rulePath, "rule must allow or deny actions")
+		}
+	}
+}
+
+func (l *aclLinter) regexp(path, pattern string) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		l.report(ACLLintError, path, "invalid regular expression: %v", err)
+	}
+}
+
+func (l *aclLinter) subjectPatterns(path string, subjects *ACLSubjects) {
+	if subjects == nil {
+		return
+	}
+	for _, pattern := range subjects.Group {
+		l.regexp(path+".group", pattern)
+	}
+	for _, pattern := range subjects.Username {
+		l.regexp(path+".username", pattern)
+	}
+	for _, pattern := range subjects.URN {
+		l.regexp(path+".urn", pattern)
+	}
+}
+
+func (l *aclLinter) lintPolicy(policy *ACLPolicy) {
+	types := aclProjectTypes
+	switch {
+	case l.project:
+		// the context is implied by the project
+	case policy.Context.Application != "":
+		types = aclApplicationTypes
+		if policy.Context.Application != "rundeck" {
+			l.report(ACLLintError, "context.application", "application context must be rundeck, not %q", policy.Context.Application)
+		}
+	default:
+		l.regexp("context.project", policy.Context.Project)
+	}
+
+	l.subjectPatterns("by", policy.By)
+	l.subjectPatterns("notBy", policy.NotBy)
+
+	for _, resourceType := range aclResourceTypes {
+		rules := policy.For.Rules(resourceType)
+		path := "for." + string(resourceType)
+		if len(rules) > 0 && !contains(types, string(resourceType)) {
+			l.report(ACLLintWarning, path, "%s rules have no effect in this context", resourceType)
+		}
+
+		for i, rule := range rules {
+			rulePath := fmt.Sprintf("%s[%d]", path, i)
+			for _, key := range sortedStringKeys(rule.Match) {
+				l.regexp(rulePath+".match."+key, rule.Match[key])
+			}
+			l.lintActions(rulePath+".allow", resourceType, rule.Allow)
+			l.lintActions(rulePath+".deny", resourceType, rule.Deny)
+
+			if overlap := overlappingActions(rule.Allow, rule.Deny); len(overlap) > 0 {
+				l.report(ACLLintWarning, rulePath, "allows and denies %s", strings.Join(overlap, ", "))
+			}
+			for j, other := range rules {
+				if i == j || len(other.Deny) == 0 || !aclSelectorsCover(other, rule) {
+					continue
+				}
+				if overlap := overlappingActions(rule.Allow, other.Deny); len(overlap) > 0 {
+					l.report(ACLLintWarning, rulePath+".allow", "%s is always denied by %s[%d]", strings.Join(overlap, ", "), path, j)
+				}
+			}
+		}
+	}
+}
+
+func (l *aclLinter) lintActions(path string, resourceType ACLResourceType, actions ACLStrings) {
+	for _, action := range actions {
+		if action == "*" || contains(aclActions[resourceType], action) {
+			continue
+		}
+		if suggestion := closestName(action, aclActions[resourceType]); suggestion != "" {
+			l.report(ACLLintWarning, path, "unknown %s action %q, did you mean %q?", resourceType, action, suggestion)
+		} else {
+			l.report(ACLLintWarning, path, "unknown %s action %q", resourceType, action)
+		}
+	}
+}
+
+// aclSelectorsCover reports whether the deny rule applies to at least everything the allow rule does,
+// which is when it has no selectors or the same ones
+func aclSelectorsCover(deny, allow *ACLRule) bool {
+	if len(deny.Match) == 0 && len(deny.Equals) == 0 && len(deny.Contains) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(deny.Match, allow.Match) &&
+		reflect.DeepEqual(deny.Equals, allow.Equals) &&
+		reflect.DeepEqual(deny.Contains, allow.Contains)
+}
+
+// overlappingActions returns the allowed actions that are also denied
+func overlappingActions(allow, deny ACLStrings) []string {
+	var overlap []string
+	for _, action := range allow {
+		if aclActionListed(deny, action) {
+			overlap = append(overlap, action)
+		} else if action == "*" {
+			overlap = append(overlap, deny...)
+		}
+	}
+	return overlap
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// closestName suggests the known name a misspelt one was most likely meant to be
+func closestName(name string, known []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range known {
+		if len(name) > 2 && strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(name)) {
+			return candidate
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,135 @@
+package rundeck_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const testInvalidACLPolicy = `description: typos everywhere
+context:
+  projet: ops
+for:
+  job:
+    - match:
+        name: '.*'
+      allow: [run, raed]
+    - deny: kill
+  node:
+    - equals:
+        nodename: db1
+by:
+  user: alice
+---
+description: overlapping rules
+context:
+  project: ops
+for:
+  job:
+    - equals:
+        group: deploy
+      allow: [run, read]
+    - equals:
+        group: deploy
+      deny: run
+    - allow: [kill]
+      deny: '*'
+`
+
+func TestLintACLPolicies(t *testing.T) {
+	issues := rundeck.LintACLPolicies("ops.aclpolicy", []byte(testInvalidACLPolicy))
+
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	report := strings.Join(lines, "\n")
+
+	for _, line := range []string{
+		`error: ops.aclpolicy[1] context.projet: unknown key "projet", did you mean "project"?`,
+		"error: ops.aclpolicy[1] context: context must have exactly one of project and application",
+		`error: ops.aclpolicy[1] by.user: unknown key "user", did you mean "username"?`,
+		"error: ops.aclpolicy[1] for.node[0]: rule must allow or deny actions",
+		"error: ops.aclpolicy[2] by: by section is required",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("expected %q in the issues:\n%s", line, report)
+		}
+	}
+	if !rundeck.ACLLintErrors(issues) {
+		t.Error("expected the policy to have errors")
+	}
+
+	// with the structure fixed, the mistakes Rundeck would accept are warnings
+	fixed := strings.Replace(testInvalidACLPolicy, "projet", "project", 1)
+	fixed = strings.Replace(fixed, "  user: alice", "  username: alice", 1)
+	fixed = strings.Replace(fixed, "        nodename: db1\n", "        nodename: db1\n      allow: read\n", 1)
+	fixed += "by:\n  group: ops\n"
+
+	issues = rundeck.LintACLPolicies("ops.aclpolicy", []byte(fixed))
+	if rundeck.ACLLintErrors(issues) {
+		t.Errorf("expected only warnings: %v\n", issues)
+	}
+	lines = nil
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	report = strings.Join(lines, "\n")
+	for _, line := range []string{
+		`warning: ops.aclpolicy[1] for.job[0].allow: unknown job action "raed", did you mean "read"?`,
+		"warning: ops.aclpolicy[2] for.job[0].allow: run is always denied by for.job[1]",
+		"warning: ops.aclpolicy[2] for.job[2]: allows and denies kill",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("expected %q in the issues:\n%s", line, report)
+		}
+	}
+
+	if issues := rundeck.LintACLPolicies("ops.aclpolicy", []byte(testACLPolicy)); len(issues) != 0 {
+		t.Errorf("expected a valid policy to have no issues: %v\n", issues)
+	}
+	notBy := strings.Replace(testACLPolicy, "by:\n", "notBy:\n  grup: contractors\nby:\n", 1)
+	issues = rundeck.LintACLPolicies("ops.aclpolicy", []byte(notBy))
+	if len(issues) != 1 || issues[0].String() != `error: ops.aclpolicy[1] notBy.grup: unknown key "grup", did you mean "group"?` {
+		t.Errorf("expected the typo under notBy to be reported: %v\n", issues)
+	}
+	notBy = strings.Replace(testACLPolicy, "by:\n", "notBy:\n  username: '(temp'\nby:\n", 1)
+	issues = rundeck.LintACLPolicies("ops.aclpolicy", []byte(notBy))
+	if len(issues) != 1 || !strings.HasPrefix(issues[0].String(), "error: ops.aclpolicy[1] notBy.username: invalid regular expression") {
+		t.Errorf("expected the invalid pattern under notBy to be reported: %v\n", issues)
+	}
+
+	if issues := rundeck.LintACLPolicies("broken.aclpolicy", []byte("for: [")); len(issues) != 1 || !strings.Contains(issues[0].Message, "invalid YAML") {
+		t.Errorf("expected invalid YAML to be reported: %v\n", issues)
+	}
+}
+
+func TestACLValidationError(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+
+	err := cli.ACL().Create("invalid", []byte(testInvalidACLPolicy))
+	var validation *rundeck.ACLValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected a validation error, received %v\n", err)
+	}
+	if len(validation.Policies) != 2 || validation.Policies[0].Policy != "invalid.aclpolicy[1]" || len(validation.Policies[0].Errors) == 0 {
+		t.Errorf("unexpected validation: %+v\n", validation.Policies)
+	}
+	if !strings.Contains(err.Error(), "invalid.aclpolicy[2]: by: by section is required") {
+		t.Errorf("unexpected error message: %v\n", err)
+	}
+	var apiErr *rundeck.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Errorf("expected the validation error to unwrap to an APIError: %v\n", err)
+	}
+
+	if err := cli.ACL().Create("valid", []byte(testACLPolicy)); err != nil {
+		t.Fatal("failed to create policy", err)
+	}
+	defer cli.ACL().Delete("valid")
+	if err := cli.ACL().Update("valid", []byte(testInvalidACLPolicy)); !errors.As(err, &validation) {
+		t.Errorf("expected a validation error updating a policy, received %v\n", err)
+	}
+}
@@ -0,0 +1,168 @@
+package rundeck
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ACLResourceType is a section of the for clause of an ACL policy
+type ACLResourceType string
+
+const (
+	ACLResourceTypeResource   ACLResourceType = "resource"
+	ACLResourceTypeAdhoc      ACLResourceType = "adhoc"
+	ACLResourceTypeJob        ACLResourceType = "job"
+	ACLResourceTypeNode       ACLResourceType = "node"
+	ACLResourceTypeProject    ACLResourceType = "project"
+	ACLResourceTypeProjectACL ACLResourceType = "project_acl"
+	ACLResourceTypeStorage    ACLResourceType = "storage"
+)
+
+// ACLPolicy is a single document of an aclpolicy file
+type ACLPolicy struct {
+	Description string       `yaml:"description,omitempty"`
+	Context     ACLContext   `yaml:"context,omitempty"`
+	For         ACLFor       `yaml:"for"`
+	By          *ACLSubjects `yaml:"by,omitempty"`
+	NotBy       *ACLSubjects `yaml:"notBy,omitempty"`
+}
+
+// ACLContext is what a policy applies to.  Only one of Project and Application is set,
+// Project being a regular expression matched against project names.
+type ACLContext struct {
+	Project     string `yaml:"project,omitempty"`
+	Application string `yaml:"application,omitempty"`
+}
+
+// ACLFor holds the rules of a policy for each type of resource.  Job, node, adhoc and storage rules
+// belong in a project context, project and project_acl rules in the application context.
+type ACLFor struct {
+	Resource   []*ACLRule `yaml:"resource,omitempty"`
+	Adhoc      []*ACLRule `yaml:"adhoc,omitempty"`
+	Job        []*ACLRule `yaml:"job,omitempty"`
+	Node       []*ACLRule `yaml:"node,omitempty"`
+	Project    []*ACLRule `yaml:"project,omitempty"`
+	ProjectACL []*ACLRule `yaml:"project_acl,omitempty"`
+	Storage    []*ACLRule `yaml:"storage,omitempty"`
+}
+
+// ACLRule allows or denies actions on the resources matching all of its selectors.  Match values are
+// regular expressions, equals values are compared exactly, contains values must all be present and
+// subset values must include every value the resource has.
+type ACLRule struct {
+	Match    map[string]string     `yaml:"match,omitempty"`
+	Equals   map[string]string     `yaml:"equals,omitempty"`
+	Contains map[string]ACLStrings `yaml:"contains,omitempty"`
+	Subset   map[string]ACLStrings `yaml:"subset,omitempty"`
+	Allow    ACLStrings            `yaml:"allow,omitempty"`
+	Deny     ACLStrings            `yaml:"deny,omitempty"`
+}
+
+// ACLSubjects is the by or notBy clause of a policy, naming who the policy applies to or who it leaves out.
+// Values are regular expressions.
+type ACLSubjects struct {
+	Group    ACLStrings `yaml:"group,omitempty"`
+	Username ACLStrings `yaml:"username,omitempty"`
+	URN      ACLStrings `yaml:"urn,omitempty"`
+}
+
+// ACLStrings is a list of strings that can be written in YAML as a single value or as a list
+type ACLStrings []string
+
+// UnmarshalYAML accepts either a single string or a list of them
+func (s *ACLStrings) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*s = list
+		return nil
+	}
+
+	var single string
+	if err := unmarshal(&single); err != nil {
+		return err
+	}
+	*s = ACLStrings{single}
+	return nil
+}
+
+// MarshalYAML writes a single value on its own
+func (s ACLStrings) MarshalYAML() (interface{}, error) {
+	if len(s) == 1 {
+		return s[0], nil
+	}
+	return []string(s), nil
+}
+
+// Rules returns the rules of the given resource type
+func (f *ACLFor) Rules(resourceType ACLResourceType) []*ACLRule {
+	switch resourceType {
+	case ACLResourceTypeResource:
+		return f.Resource
+	case ACLResourceTypeAdhoc:
+		return f.Adhoc
+	case ACLResourceTypeJob:
+		return f.Job
+	case ACLResourceTypeNode:
+		return f.Node
+	case ACLResourceTypeProject:
+		return f.Project
+	case ACLResourceTypeProjectACL:
+		return f.ProjectACL
+	case ACLResourceTypeStorage:
+		return f.Storage
+	}
+	return nil
+}
+
+// ParseACLPolicies parses the documents of an aclpolicy file.  Empty documents are skipped.  Unknown keys
+// are an error rather than being dropped, so that a parsed policy never grants more than the file does.
+func ParseACLPolicies(content []byte) ([]*ACLPolicy, error) {
+	var policies []*ACLPolicy
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.SetStrict(true)
+	for {
+		var policy *ACLPolicy
+		err := decoder.Decode(&policy)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// MarshalACLPolicies writes policies as a multi-document aclpolicy file
+func MarshalACLPolicies(policies []*ACLPolicy) ([]byte, error) {
+	documents := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		document, err := yaml.Marshal(policy)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, string(document))
+	}
+	return []byte(strings.Join(documents, "---\n")), nil
+}
+
+// GetPolicies retrieves an ACL policy file and parses its documents
+func (a *ACL) GetPolicies(name string) ([]*ACLPolicy, error) {
+	return a.GetPoliciesWithContext(context.Background(), name)
+}
+
+// GetPoliciesWithContext is the same as GetPolicies with the addition of the ability to pass a context.
+func (a *ACL) GetPoliciesWithContext(ctx context.Context, name string) ([]*ACLPolicy, error) {
+	content, err := a.GetWithContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return ParseACLPolicies(content)
+}
@@ -0,0 +1,164 @@
+package rundeck_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const testACLPolicy = `description: Ops can run jobs
+context:
+  project: 'ops-.*'
+for:
+  resource:
+    - equals:
+        kind: job
+      allow: [create]
+  job:
+    - match:
+        group: 'deploy/.*'
+      allow: [run, read]
+    - equals:
+        name: 'drop database'
+      deny: '*'
+  node:
+    - contains:
+        tags: [prod, db]
+      allow: read
+  storage:
+    - match:
+        path: 'keys/ops/.*'
+      allow: [read]
+by:
+  group: ops
+---
+---
+description: Ops can see projects
+context:
+  application: rundeck
+for:
+  project:
+    - match:
+        name: 'ops-.*'
+      allow: [read]
+by:
+  username: [alice, bob]
+  urn: 'urn:ldap:.*'
+`
+
+func TestACLPolicyRoundTrip(t *testing.T) {
+	policies, err := rundeck.ParseACLPolicies([]byte(testACLPolicy))
+	if err != nil {
+		t.Fatal("failed to parse policies", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 policies, received %d\n", len(policies))
+	}
+
+	project := policies[0]
+	if project.Context.Project != "ops-.*" || project.By.Group[0] != "ops" {
+		t.Errorf("unexpected policy: %+v\n", project)
+	}
+	jobs := project.For.Rules(rundeck.ACLResourceTypeJob)
+	if len(jobs) != 2 || jobs[0].Match["group"] != "deploy/.*" || !reflect.DeepEqual(jobs[0].Allow, rundeck.ACLStrings{"run", "read"}) {
+		t.Errorf("unexpected job rules: %+v\n", jobs)
+	}
+	if !reflect.DeepEqual(jobs[1].Deny, rundeck.ACLStrings{"*"}) {
+		t.Errorf("expected a single deny action to parse as a list: %+v\n", jobs[1])
+	}
+	if !reflect.DeepEqual(project.For.Node[0].Contains["tags"], rundeck.ACLStrings{"prod", "db"}) {
+		t.Errorf("unexpected node rule: %+v\n", project.For.Node[0])
+	}
+
+	application := policies[1]
+	if application.Context.Application != "rundeck" || len(application.By.Username) != 2 || application.By.URN[0] != "urn:ldap:.*" {
+		t.Errorf("unexpected policy: %+v\n", application)
+	}
+
+	content, err := rundeck.MarshalACLPolicies(policies)
+	if err != nil {
+		t.Fatal("failed to marshal policies", err)
+	}
+	if strings.Count(string(content), "---\n") != 1 {
+		t.Errorf("expected two documents:\n%s", content)
+	}
+
+	again, err := rundeck.ParseACLPolicies(content)
+	if err != nil {
+		t.Fatal("failed to parse marshaled policies", err)
+	}
+	if !reflect.DeepEqual(policies, again) {
+		t.Errorf("policies changed after a round trip:\n%s", content)
+	}
+}
+
+func TestACLGetPolicies(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+
+	policies := []*rundeck.ACLPolicy{{
+		Description: "generated",
+		Context:     rundeck.ACLContext{Project: ".*"},
+		For: rundeck.ACLFor{
+			Job: []*rundeck.ACLRule{{Equals: map[string]string{"group": "reports"}, Allow: rundeck.ACLStrings{"read"}}},
+		},
+		By: &rundeck.ACLSubjects{Group: rundeck.ACLStrings{"auditors"}},
+	}}
+	content, err := rundeck.MarshalACLPolicies(policies)
+	if err != nil {
+		t.Fatal("failed to marshal policies", err)
+	}
+
+	if err := cli.ACL().Create("generated", content); err != nil {
+		t.Fatal("failed to create policy", err)
+	}
+	defer cli.ACL().Delete("generated")
+
+	fetched, err := cli.ACL().GetPolicies("generated")
+	if err != nil {
+		t.Fatal("failed to get policies", err)
+	}
+	if !reflect.DeepEqual(policies, fetched) {
+		t.Errorf("unexpected policies: %+v\n", fetched)
+	}
+}
+
+func TestACLPolicySelectors(t *testing.T) {
+	const policy = `context:
+  project: ops
+for:
+  job:
+    - subset:
+        tags: [prod]
+      allow: run
+by:
+  group: ops
+notBy:
+  username: mallory
+`
+	policies, err := rundeck.ParseACLPolicies([]byte(policy))
+	if err != nil {
+		t.Fatal("failed to parse policies", err)
+	}
+	if policies[0].NotBy == nil || policies[0].NotBy.Username[0] != "mallory" {
+		t.Errorf("expected notBy to be parsed: %+v\n", policies[0])
+	}
+	if !reflect.DeepEqual(policies[0].For.Job[0].Subset["tags"], rundeck.ACLStrings{"prod"}) {
+		t.Errorf("expected subset to be parsed: %+v\n", policies[0].For.Job[0])
+	}
+
+	content, err := rundeck.MarshalACLPolicies(policies)
+	if err != nil {
+		t.Fatal("failed to marshal policies", err)
+	}
+	for _, key := range []string{"notBy:", "subset:"} {
+		if !strings.Contains(string(content), key) {
+			t.Errorf("expected %s to be marshaled:\n%s", key, content)
+		}
+	}
+
+	if _, err := rundeck.ParseACLPolicies([]byte(strings.Replace(policy, "subset:", "superset:", 1))); err == nil {
+		t.Error("expected an unknown key to be an error rather than dropped")
+	}
+}
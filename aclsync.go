@@ -0,0 +1,224 @@
+package rundeck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ACLSyncAction is what ACL.Sync does to a single policy file
+type ACLSyncAction string
+
+const (
+	// ACLSyncActionCreate stores a policy file that only exists locally
+	ACLSyncActionCreate ACLSyncAction = "create"
+	// ACLSyncActionUpdate replaces a stored policy whose text differs from its file
+	ACLSyncActionUpdate ACLSyncAction = "update"
+	// ACLSyncActionDelete removes a stored policy without a file, only planned when pruning
+	ACLSyncActionDelete ACLSyncAction = "delete"
+)
+
+// ACLSyncOptions are the optional parameters for ACL.Sync
+type ACLSyncOptions struct {
+	// DryRun lints the files and plans the changes, but leaves the stored policies alone
+	DryRun bool
+
+	// Prune deletes policies in the scope that have no local file
+	Prune bool
+
+	// Output receives the plan, with the lint warnings of the files, before anything is changed
+	Output io.Writer
+}
+
+// ACLSyncChange is a single planned change
+type ACLSyncChange struct {
+	Action ACLSyncAction
+	Name   string
+	// Content is the local policy for creates and updates
+	Content []byte
+}
+
+// ACLSyncPlan is the set of changes needed to make the policies of a scope match the local files
+type ACLSyncPlan struct {
+	Scope     ACLScope
+	Changes   []*ACLSyncChange
+	Unchanged []string
+	// Warnings are the lint warnings of the local files
+	Warnings []*ACLLintIssue
+}
+
+// ACLSyncResult is the outcome of ACL.Sync, listing the policies that were changed
+type ACLSyncResult struct {
+	Plan    *ACLSyncPlan
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// Count returns the number of policies planned for the given action
+func (p *ACLSyncPlan) Count(action ACLSyncAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String lists the policy names to change, followed by the lint warnings
+func (p *ACLSyncPlan) String() string {
+	var b strings.Builder
+	writeSyncPlanSummary(&b, p.Scope.String()+" ACL policies",
+		p.Count(ACLSyncActionCreate), p.Count(ACLSyncActionUpdate), p.Count(ACLSyncActionDelete), len(p.Unchanged))
+
+	for _, change := range p.Changes {
+		fmt.Fprintf(&b, "  %s %s\n", syncActionSymbol(string(change.Action)), change.Name)
+	}
+	for _, warning := range p.Warnings {
+		b.WriteString("  " + warning.String() + "\n")
+	}
+	return b.String()
+}
+
+// Sync reconciles the policies of a scope with the *.aclpolicy files of a local directory, like a plan and apply.
+//
+// Files are matched to stored policies by name.  Every file is linted first and the sync stops before changing
+// anything when one has errors.  Policies whose text differs from the file are updated, and when opts.Prune
+// is set policies without a file are deleted.
+func (a *ACL) Sync(ctx context.Context, scope ACLScope, dir string, opts *ACLSyncOptions) (*ACLSyncResult, error) {
+	if opts == nil {
+		opts = &ACLSyncOptions{}
+	}
+
+	plan, err := a.planSync(ctx, scope, dir, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, plan.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ACLSyncResult{Plan: plan}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, change := range plan.Changes {
+		url := a.c.aclURL(scope, change.Name)
+
+		var err error
+		switch change.Action {
+		case ACLSyncActionCreate:
+			if err = a.c.createACL(ctx, url, change.Content); err == nil {
+				result.Created = append(result.Created, change.Name)
+			}
+		case ACLSyncActionUpdate:
+			if err = a.c.updateACL(ctx, url, change.Content); err == nil {
+				result.Updated = append(result.Updated, change.Name)
+			}
+		case ACLSyncActionDelete:
+			if err = a.c.deleteACL(ctx, url); err == nil {
+				result.Deleted = append(result.Deleted, change.Name)
+			}
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to %s %s: %w", change.Action, change.Name, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *ACL) planSync(ctx context.Context, scope ACLScope, dir string, prune bool) (*ACLSyncPlan, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+aclPolicySuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ACLSyncPlan{Scope: scope}
+	local := make(map[string][]byte, len(files))
+	var lintErrors []string
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		name := sanitizeACLName(filepath.Base(file))
+		local[name] = content
+
+		var issues []*ACLLintIssue
+		if scope.Project != "" {
+			issues = LintProjectACLPolicies(name, content)
+		} else {
+			issues = LintACLPolicies(name, content)
+		}
+		for _, issue := range issues {
+			if issue.Severity == ACLLintError {
+				lintErrors = append(lintErrors, issue.String())
+			} else {
+				plan.Warnings = append(plan.Warnings, issue)
+			}
+		}
+	}
+	if len(lintErrors) > 0 {
+		return nil, fmt.Errorf("invalid ACL policies:\n%s", strings.Join(lintErrors, "\n"))
+	}
+
+	list, err := a.c.listACLs(ctx, a.c.aclURL(scope, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	remote := make(map[string]bool)
+	for _, resource := range list.Resources {
+		if resource.Type != "file" {
+			continue
+		}
+		name := sanitizeACLName(resource.Name)
+		remote[name] = true
+
+		content, ok := local[name]
+		if !ok {
+			if prune {
+				plan.Changes = append(plan.Changes, &ACLSyncChange{Action: ACLSyncActionDelete, Name: name})
+			}
+			continue
+		}
+
+		current, err := a.c.getACL(ctx, a.c.aclURL(scope, name))
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(bytes.TrimSpace(current), bytes.TrimSpace(content)) {
+			plan.Unchanged = append(plan.Unchanged, name)
+			continue
+		}
+		plan.Changes = append(plan.Changes, &ACLSyncChange{Action: ACLSyncActionUpdate, Name: name, Content: content})
+	}
+
+	for name, content := range local {
+		if !remote[name] {
+			plan.Changes = append(plan.Changes, &ACLSyncChange{Action: ACLSyncActionCreate, Name: name, Content: content})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		ci, cj := plan.Changes[i], plan.Changes[j]
+		if ci.Action != cj.Action {
+			return syncActionRank(string(ci.Action)) < syncActionRank(string(cj.Action))
+		}
+		return ci.Name < cj.Name
+	})
+	sort.Strings(plan.Unchanged)
+
+	return plan, nil
+}
@@ -0,0 +1,125 @@
+package rundeck_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func writeACLFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "aclsync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestACLSync(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+	ctx := context.Background()
+
+	changed := strings.Replace(testACLPolicy, "Ops can run jobs", "Ops can run and read jobs", 1)
+	for name, content := range map[string]string{"same": testACLPolicy, "changed": testACLPolicy, "orphan": testACLPolicy} {
+		if err := cli.ACL().Create(name, []byte(content)); err != nil {
+			t.Fatal("failed to seed policies", err)
+		}
+	}
+
+	dir := writeACLFiles(t, map[string]string{
+		"same.aclpolicy":    testACLPolicy,
+		"changed.aclpolicy": changed,
+		"new.aclpolicy":     strings.Replace(testACLPolicy, "[run, read]", "[run, raed]", 1),
+		"README.md":         "not a policy",
+	})
+	defer os.RemoveAll(dir)
+
+	var out bytes.Buffer
+	result, err := cli.ACL().Sync(ctx, rundeck.SystemACLScope, dir, &rundeck.ACLSyncOptions{DryRun: true, Prune: true, Output: &out})
+	if err != nil {
+		t.Fatal("failed to plan sync", err)
+	}
+	plan := result.Plan
+	if plan.Count(rundeck.ACLSyncActionCreate) != 1 || plan.Count(rundeck.ACLSyncActionUpdate) != 1 || plan.Count(rundeck.ACLSyncActionDelete) != 1 || len(plan.Unchanged) != 1 {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+	for _, line := range []string{
+		"Plan for system ACL policies: 1 to create, 1 to update, 1 to delete, 1 unchanged",
+		"+ new.aclpolicy",
+		"~ changed.aclpolicy",
+		"- orphan.aclpolicy",
+		`warning: new.aclpolicy[1] for.job[0].allow: unknown job action "raed"`,
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in the printed plan:\n%s", line, out.String())
+		}
+	}
+	if _, err := cli.ACL().Get("new"); err == nil {
+		t.Error("a dry run should not change anything")
+	}
+
+	result, err = cli.ACL().Sync(ctx, rundeck.SystemACLScope, dir, &rundeck.ACLSyncOptions{Prune: true})
+	if err != nil {
+		t.Fatal("failed to sync policies", err)
+	}
+	if len(result.Created) != 1 || len(result.Updated) != 1 || len(result.Deleted) != 1 {
+		t.Errorf("unexpected result: %+v\n", result)
+	}
+	content, err := cli.ACL().Get("changed")
+	if err != nil || string(content) != changed {
+		t.Errorf("expected the policy to be updated: %s %v\n", content, err)
+	}
+
+	result, err = cli.ACL().Sync(ctx, rundeck.SystemACLScope, dir, nil)
+	if err != nil {
+		t.Fatal("failed to sync policies", err)
+	}
+	if len(result.Plan.Changes) != 0 {
+		t.Errorf("expected nothing left to change:\n%s", result.Plan)
+	}
+}
+
+func TestACLSyncProject(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+	ctx := context.Background()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Synced"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	dir := writeACLFiles(t, map[string]string{"deploy.aclpolicy": testProjectACLPolicy})
+	defer os.RemoveAll(dir)
+
+	result, err := cli.ACL().Sync(ctx, rundeck.ProjectACLScope("Synced"), dir, nil)
+	if err != nil {
+		t.Fatal("failed to sync project policies", err)
+	}
+	if len(result.Created) != 1 {
+		t.Errorf("unexpected result: %+v\n", result)
+	}
+	if _, err := cli.ProjectACL().Get("Synced", "deploy"); err != nil {
+		t.Error("expected the project policy to be created", err)
+	}
+
+	// system policies name a context, which project policies can't
+	invalid := writeACLFiles(t, map[string]string{"system.aclpolicy": testACLPolicy})
+	defer os.RemoveAll(invalid)
+	if _, err := cli.ACL().Sync(ctx, rundeck.ProjectACLScope("Synced"), invalid, nil); err == nil || !strings.Contains(err.Error(), "context is implied by the project") {
+		t.Errorf("expected lint errors to stop the sync, received %v\n", err)
+	}
+}
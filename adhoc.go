@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 )
@@ -59,6 +60,11 @@ func (c *Client) Adhoc() *AdhocAPI {
 
 // RunCommandString runs an adhoc command
 func (a *AdhocAPI) RunCommandString(input *AdhocCommandStringInput) (*AdhocCommandResponse, error) {
+	return a.RunCommandStringWithContext(context.Background(), input)
+}
+
+// RunCommandStringWithContext is the same as RunCommandString with the addition of the ability to pass a context.
+func (a *AdhocAPI) RunCommandStringWithContext(ctx context.Context, input *AdhocCommandStringInput) (*AdhocCommandResponse, error) {
 	if input == nil {
 		return nil, errors.New("input cannot be nil")
 	}
@@ -78,7 +84,7 @@ func (a *AdhocAPI) RunCommandString(input *AdhocCommandStringInput) (*AdhocComma
 		return nil, err
 	}
 
-	res, err := a.c.checkResponseOK(a.c.post(rawURL, bytes.NewReader(bs)))
+	res, err := a.c.checkResponseOK(a.c.post(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +96,11 @@ func (a *AdhocAPI) RunCommandString(input *AdhocCommandStringInput) (*AdhocComma
 
 // RunScript runs a script
 func (a *AdhocAPI) RunScript(input *AdhocScriptInput) (*AdhocCommandResponse, error) {
+	return a.RunScriptWithContext(context.Background(), input)
+}
+
+// RunScriptWithContext is the same as RunScript with the addition of the ability to pass a context.
+func (a *AdhocAPI) RunScriptWithContext(ctx context.Context, input *AdhocScriptInput) (*AdhocCommandResponse, error) {
 	if input == nil {
 		return nil, errors.New("input cannot be nil")
 	}
@@ -109,7 +120,7 @@ func (a *AdhocAPI) RunScript(input *AdhocScriptInput) (*AdhocCommandResponse, er
 		return nil, err
 	}
 
-	res, err := a.c.checkResponseOK(a.c.post(rawURL, bytes.NewReader(bs)))
+	res, err := a.c.checkResponseOK(a.c.post(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +132,11 @@ func (a *AdhocAPI) RunScript(input *AdhocScriptInput) (*AdhocCommandResponse, er
 
 // RunURL runs a script downloaded from a url
 func (a *AdhocAPI) RunURL(input *AdhocURLInput) (*AdhocCommandResponse, error) {
+	return a.RunURLWithContext(context.Background(), input)
+}
+
+// RunURLWithContext is the same as RunURL with the addition of the ability to pass a context.
+func (a *AdhocAPI) RunURLWithContext(ctx context.Context, input *AdhocURLInput) (*AdhocCommandResponse, error) {
 	if input == nil {
 		return nil, errors.New("input cannot be nil")
 	}
@@ -140,7 +156,7 @@ func (a *AdhocAPI) RunURL(input *AdhocURLInput) (*AdhocCommandResponse, error) {
 		return nil, err
 	}
 
-	res, err := a.c.checkResponseOK(a.c.post(rawURL, bytes.NewReader(bs)))
+	res, err := a.c.checkResponseOK(a.c.post(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -1,17 +1,25 @@
 package rundeck
 
 import (
+	"bytes"
+	"context"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Client is the basic client that interacts with the Rundeck API.
 type Client struct {
 	Config      *Config
-	client      *http.Client
 	RundeckAddr string
+
+	// mu guards client and the token in Config, which SetAPIToken replaces while requests may be in flight
+	mu     sync.RWMutex
+	client *http.Client
 }
 
 // NewClient returns a rundeck client
@@ -25,28 +33,55 @@ func NewClient(config *Config) *Client {
 	}
 
 	return &Client{
-		Config: config,
-		client: &http.Client{
-			Jar: http.DefaultClient.Jar,
-			Transport: &rundeckTransport{
-				apiToken:            config.RundeckAuthToken,
-				underlyingTransport: http.DefaultTransport,
-			},
-		},
+		Config:      config,
+		client:      newHTTPClient(config),
 		RundeckAddr: sanitizeAddr(config.ServerURL) + "/api/" + strconv.Itoa(config.APIVersion),
 	}
 }
 
 // SetAPIToken will update the token (and associated client transport for the API calls)
+//
+// Any http.Client or transport supplied through the Config is preserved.  It is safe to call while
+// other goroutines use the client: requests already sent keep the old token, later ones use the new one.
 func (c *Client) SetAPIToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.Config.RundeckAuthToken = token
-	c.client = &http.Client{
+	c.client = newHTTPClient(c.Config)
+}
+
+// httpClient returns the http.Client currently used for API calls
+func (c *Client) httpClient() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// newHTTPClient builds the http.Client used for API calls, wrapping the configured
+// transport so that every request carries the Rundeck auth token.
+func newHTTPClient(config *Config) *http.Client {
+	client := &http.Client{
 		Jar: http.DefaultClient.Jar,
-		Transport: &rundeckTransport{
-			apiToken:            c.Config.RundeckAuthToken,
-			underlyingTransport: http.DefaultTransport,
-		},
 	}
+	if config.HTTPClient != nil {
+		// copy so the caller's client is never mutated
+		cp := *config.HTTPClient
+		client = &cp
+	}
+
+	underlyingTransport := http.DefaultTransport
+	if config.Transport != nil {
+		underlyingTransport = config.Transport
+	} else if client.Transport != nil {
+		underlyingTransport = client.Transport
+	}
+
+	client.Transport = &rundeckTransport{
+		apiToken:            config.RundeckAuthToken,
+		underlyingTransport: underlyingTransport,
+	}
+
+	return client
 }
 
 // sanitizeAddr will remove all trailing slashes from the supplied ServerURL to ensure path correctness
@@ -57,73 +92,82 @@ func sanitizeAddr(addr string) string {
 	return addr
 }
 
-func (c *Client) get(url string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.client.Do(req)
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, url, nil, nil)
 }
 
-func (c *Client) getWithAdditionalHeaders(url string, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	c.addHeaders(req, headers)
-
-	return c.client.Do(req)
+func (c *Client) getWithAdditionalHeaders(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, url, headers, nil)
 }
 
-func (c *Client) post(url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.client.Do(req)
+func (c *Client) post(ctx context.Context, url string, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodPost, url, nil, body)
 }
 
-func (c *Client) postWithAdditionalHeaders(url string, headers map[string]string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	c.addHeaders(req, headers)
-
-	return c.client.Do(req)
+func (c *Client) postWithAdditionalHeaders(ctx context.Context, url string, headers map[string]string, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodPost, url, headers, body)
 }
 
-func (c *Client) put(url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPut, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.client.Do(req)
+func (c *Client) put(ctx context.Context, url string, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodPut, url, nil, body)
 }
 
-func (c *Client) putWithAdditionalHeaders(url string, headers map[string]string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPut, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	c.addHeaders(req, headers)
+func (c *Client) putWithAdditionalHeaders(ctx context.Context, url string, headers map[string]string, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodPut, url, headers, body)
+}
 
-	return c.client.Do(req)
+func (c *Client) delete(ctx context.Context, url string, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodDelete, url, nil, body)
 }
 
-func (c *Client) delete(url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete, url, body)
-	if err != nil {
-		return nil, err
+// do sends the request, retrying transient failures according to Config.Retry.
+// When the request may be retried, the body is buffered up front so that it can be replayed
+// on every attempt.  Otherwise it is streamed as it is.
+func (c *Client) do(ctx context.Context, method, url string, headers map[string]string, body io.Reader) (*http.Response, error) {
+	policy := c.Config.Retry
+
+	var content []byte
+	buffered := body != nil && policy.mayRetry(method)
+	if buffered {
+		bs, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		content = bs
 	}
 
-	return c.client.Do(req)
+	for attempt := 1; ; attempt++ {
+		reqBody := body
+		if buffered {
+			reqBody = bytes.NewReader(content)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		c.addHeaders(req, headers)
+
+		res, err := c.httpClient().Do(req)
+		if !policy.shouldRetry(ctx, method, attempt, res, err) {
+			return res, err
+		}
+
+		wait := policy.backoff(attempt, res)
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
 }
 
 func (c *Client) addHeaders(req *http.Request, headers map[string]string) {
@@ -157,7 +201,7 @@ func (c *Client) checkResponse(res *http.Response, statusCode int, err error) (*
 		return nil, err
 	}
 	if res.StatusCode != statusCode {
-		return nil, makeError(res.Body)
+		return nil, makeError(res)
 	}
 	return res, nil
 }
@@ -1,9 +1,17 @@
 package rundeck_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/andrewmeissner/go-rundeck"
 )
@@ -35,3 +43,152 @@ func TestExtraSlashesOnRundeckAddr(t *testing.T) {
 		t.Error("failed to sanitize rundeck addr")
 	}
 }
+
+func TestCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cli := rundeck.NewClient(&rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: "dev-token",
+		ServerURL:        "http://localhost:4440",
+	})
+
+	_, err := cli.System().InfoWithContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v\n", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCustomTransportSurvivesSetAPIToken(t *testing.T) {
+	var tokens []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		tokens = append(tokens, req.Header.Get("X-Rundeck-Auth-Token"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("[]")),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+
+	cli := rundeck.NewClient(&rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: "first-token",
+		ServerURL:        "http://localhost:4440",
+		Transport:        transport,
+	})
+
+	if _, err := cli.Tokens().List(); err != nil {
+		t.Error("listing tokens through custom transport failed", err)
+	}
+
+	cli.SetAPIToken("second-token")
+
+	if _, err := cli.Tokens().List(); err != nil {
+		t.Error("listing tokens through custom transport failed", err)
+	}
+
+	if len(tokens) != 2 || tokens[0] != "first-token" || tokens[1] != "second-token" {
+		t.Errorf("custom transport was not used for every request.  tokens seen: %v\n", tokens)
+	}
+}
+
+// run with -race: the token is switched while other goroutines send requests through the client,
+// the way Tokens.Rotate does with SwitchClient
+func TestSetAPITokenWhileInUse(t *testing.T) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("[]")),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+
+	cli := rundeck.NewClient(&rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: "first-token",
+		ServerURL:        "http://localhost:4440",
+		Transport:        transport,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := cli.Tokens().List(); err != nil {
+					t.Error("listing tokens failed during the switch", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		cli.SetAPIToken(fmt.Sprintf("token-%d", i))
+	}
+	wg.Wait()
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	var gets, posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+		} else {
+			gets++
+		}
+		if (r.Method == http.MethodPost && posts < 3) || (r.Method == http.MethodGet && gets < 3) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"login":"admin"}`))
+	}))
+	defer server.Close()
+
+	policy := rundeck.DefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+
+	cli := rundeck.NewClient(&rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: "dev-token",
+		ServerURL:        server.URL,
+		Retry:            policy,
+	})
+
+	if _, err := cli.Users().Get(nil); err != nil {
+		t.Error("get should have succeeded after retrying", err)
+	}
+	if gets != 3 {
+		t.Errorf("unexpected number of get attempts.  expected: 3\tactual: %d\n", gets)
+	}
+
+	if _, err := cli.Users().Modify(nil, &rundeck.ModifyUserInput{FirstName: "admin"}); err == nil {
+		t.Error("post should not be retried by default")
+	}
+	if posts != 1 {
+		t.Errorf("unexpected number of post attempts.  expected: 1\tactual: %d\n", posts)
+	}
+
+	policy.RetryNonIdempotent = true
+	if _, err := cli.Users().Modify(nil, &rundeck.ModifyUserInput{FirstName: "admin"}); err != nil {
+		t.Error("post should have been retried with a replayed body", err)
+	}
+	if posts != 3 {
+		t.Errorf("unexpected number of post attempts.  expected: 3\tactual: %d\n", posts)
+	}
+}
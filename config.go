@@ -1,11 +1,17 @@
 package rundeck
 
-import "os"
+import (
+	"net/http"
+	"os"
+)
 
 const (
 	// APIVersion24 is defaulted to the specified api version
 	APIVersion24 = 24
 
+	// APIVersion37 is the first api version supporting named tokens
+	APIVersion37 = 37
+
 	// EnvRundeckToken sets the name of the environment variable to read
 	EnvRundeckToken = "RUNDECK_TOKEN"
 
@@ -25,6 +31,18 @@ type Config struct {
 
 	// RundeckAuthToken is the authentication token used to communicate with Rundeck
 	RundeckAuthToken string
+
+	// HTTPClient is an optional client used to communicate with Rundeck.  Use it to set
+	// timeouts, cookie jars, or a transport configured with TLS roots, client certificates or proxies.
+	// The client is copied and its transport is wrapped to supply the auth token.
+	HTTPClient *http.Client
+
+	// Transport is an optional http.RoundTripper used for the API calls.  It takes precedence
+	// over the transport of HTTPClient.  If neither is set, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// Retry is an optional policy for retrying transient failures.  If nil, requests are never retried.
+	Retry *RetryPolicy
 }
 
 // DefaultConfig implements a localhost basic configuration, relying on and assuming a valid api token
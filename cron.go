@@ -0,0 +1,510 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cronMinYear = 1970
+	cronMaxYear = 2099
+)
+
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var cronDayNames = map[string]int{
+	"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+}
+
+// CronSchedule is a parsed Quartz cron expression, the schedule syntax Rundeck uses.
+//
+// The fields are seconds, minutes, hours, day of month, month, day of week and an optional year.
+// Lists, ranges, steps and names are supported, along with L, W, LW and # in the day fields.
+type CronSchedule struct {
+	expr string
+
+	seconds, minutes, hours uint64
+	daysOfMonth             uint64
+	months                  uint64
+	daysOfWeek              uint64
+	years                   []bool
+
+	// day of month specials
+	anyDayOfMonth  bool
+	lastDay        bool
+	lastDayOffset  int
+	lastWeekday    bool
+	nearestWeekday int
+
+	// day of week specials
+	anyDayOfWeek bool
+	lastOfWeek   bool
+	nthOfWeek    int
+}
+
+// ParseCron parses a Quartz cron expression, e.g. 0 30 2 ? * MON-FRI *
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(strings.ToUpper(expr))
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf("cron expression %q must have 6 or 7 fields", expr)
+	}
+	if (fields[3] == "?") == (fields[5] == "?") {
+		return nil, fmt.Errorf("cron expression %q must use '?' in exactly one of day of month and day of week", expr)
+	}
+
+	c := &CronSchedule{expr: expr}
+	var err error
+	if c.seconds, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron expression %q seconds: %v", expr, err)
+	}
+	if c.minutes, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron expression %q minutes: %v", expr, err)
+	}
+	if c.hours, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron expression %q hours: %v", expr, err)
+	}
+	if err = c.parseDayOfMonth(fields[3]); err != nil {
+		return nil, fmt.Errorf("cron expression %q day of month: %v", expr, err)
+	}
+	if c.months, err = parseCronField(fields[4], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("cron expression %q month: %v", expr, err)
+	}
+	if err = c.parseDayOfWeek(fields[5]); err != nil {
+		return nil, fmt.Errorf("cron expression %q day of week: %v", expr, err)
+	}
+	if len(fields) == 7 && fields[6] != "*" && fields[6] != "?" {
+		c.years = make([]bool, cronMaxYear-cronMinYear+1)
+		if err = parseCronYears(fields[6], c.years); err != nil {
+			return nil, fmt.Errorf("cron expression %q year: %v", expr, err)
+		}
+	}
+
+	return c, nil
+}
+
+// ParseJobSchedule parses the schedule of a job definition, in either its crontab or structured form
+func ParseJobSchedule(schedule *JobSchedule) (*CronSchedule, error) {
+	if schedule == nil {
+		return nil, fmt.Errorf("schedule cannot be nil")
+	}
+	return ParseCron(schedule.CronExpression())
+}
+
+// String returns the expression the schedule was parsed from
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Next returns the first fire time strictly after t, in t's location.  The zero time is returned
+// when the schedule never fires again.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	start := t.Truncate(time.Second).Add(time.Second)
+
+	y, m, d := start.Date()
+	startHour, startMinute, startSecond := start.Clock()
+
+	// walk calendar days in UTC so DST transitions never skip or repeat a day
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for day.Year() <= cronMaxYear {
+		if !c.matchesYear(day.Year()) || c.months&(1<<uint(day.Month())) == 0 {
+			day = time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			startHour, startMinute, startSecond = 0, 0, 0
+			continue
+		}
+
+		if c.matchesDay(day) {
+			if next, ok := c.nextTimeOfDay(day, startHour, startMinute, startSecond, loc, t); ok {
+				return next
+			}
+		}
+
+		day = day.AddDate(0, 0, 1)
+		startHour, startMinute, startSecond = 0, 0, 0
+	}
+
+	return time.Time{}
+}
+
+// NextN returns up to n fire times strictly after t, in the given location.  When loc is nil,
+// t's location is used.
+func (c *CronSchedule) NextN(t time.Time, n int, loc *time.Location) []time.Time {
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	var times []time.Time
+	for len(times) < n {
+		t = c.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// Between returns every fire time after from and up to and including to
+func (c *CronSchedule) Between(from, to time.Time) []time.Time {
+	var times []time.Time
+	for t := c.Next(from); !t.IsZero() && !t.After(to); t = c.Next(t) {
+		times = append(times, t)
+	}
+	return times
+}
+
+func (c *CronSchedule) nextTimeOfDay(day time.Time, startHour, startMinute, startSecond int, loc *time.Location, after time.Time) (time.Time, bool) {
+	for h := startHour; h < 24; h++ {
+		if c.hours&(1<<uint(h)) == 0 {
+			continue
+		}
+		minMinute := 0
+		if h == startHour {
+			minMinute = startMinute
+		}
+		for mi := minMinute; mi < 60; mi++ {
+			if c.minutes&(1<<uint(mi)) == 0 {
+				continue
+			}
+			minSecond := 0
+			if h == startHour && mi == startMinute {
+				minSecond = startSecond
+			}
+			for s := minSecond; s < 60; s++ {
+				if c.seconds&(1<<uint(s)) == 0 {
+					continue
+				}
+				candidate := time.Date(day.Year(), day.Month(), day.Day(), h, mi, s, 0, loc)
+				// wall clock times inside a DST gap do not exist and are skipped
+				if candidate.Hour() != h || candidate.Minute() != mi || candidate.Day() != day.Day() {
+					continue
+				}
+				if candidate.After(after) {
+					return candidate, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (c *CronSchedule) matchesYear(year int) bool {
+	if c.years == nil {
+		return true
+	}
+	if year < cronMinYear || year > cronMaxYear {
+		return false
+	}
+	return c.years[year-cronMinYear]
+}
+
+func (c *CronSchedule) matchesDay(day time.Time) bool {
+	lastDayOfMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	dom := day.Day()
+	dow := int(day.Weekday()) + 1
+
+	if c.anyDayOfWeek {
+		switch {
+		case c.lastWeekday:
+			return dom == lastWeekdayOfMonth(day.Year(), day.Month(), lastDayOfMonth)
+		case c.lastDay:
+			return dom == lastDayOfMonth-c.lastDayOffset
+		case c.nearestWeekday > 0:
+			return dom == nearestWeekday(day.Year(), day.Month(), c.nearestWeekday, lastDayOfMonth)
+		case c.anyDayOfMonth:
+			return true
+		}
+		return c.daysOfMonth&(1<<uint(dom)) != 0
+	}
+
+	if c.daysOfWeek&(1<<uint(dow)) == 0 {
+		return false
+	}
+	switch {
+	case c.lastOfWeek:
+		return dom+7 > lastDayOfMonth
+	case c.nthOfWeek > 0:
+		return (dom-1)/7+1 == c.nthOfWeek
+	}
+	return true
+}
+
+func (c *CronSchedule) parseDayOfMonth(field string) error {
+	switch {
+	case field == "?":
+		c.anyDayOfMonth = true
+		return nil
+	case field == "LW":
+		c.lastWeekday = true
+		return nil
+	case field == "L":
+		c.lastDay = true
+		return nil
+	case strings.HasPrefix(field, "L-"):
+		offset, err := strconv.Atoi(field[2:])
+		if err != nil || offset < 0 || offset > 30 {
+			return fmt.Errorf("invalid offset %q", field)
+		}
+		c.lastDay = true
+		c.lastDayOffset = offset
+		return nil
+	case strings.HasSuffix(field, "W"):
+		day, err := strconv.Atoi(strings.TrimSuffix(field, "W"))
+		if err != nil || day < 1 || day > 31 {
+			return fmt.Errorf("invalid weekday %q", field)
+		}
+		c.nearestWeekday = day
+		return nil
+	}
+
+	var err error
+	c.daysOfMonth, err = parseCronField(field, 1, 31, nil)
+	return err
+}
+
+func (c *CronSchedule) parseDayOfWeek(field string) error {
+	switch {
+	case field == "?":
+		c.anyDayOfWeek = true
+		return nil
+	case field == "L":
+		c.daysOfWeek = 1 << 7
+		return nil
+	case len(field) > 1 && strings.HasSuffix(field, "L"):
+		day, err := parseCronValue(strings.TrimSuffix(field, "L"), 1, 7, cronDayNames)
+		if err != nil {
+			return err
+		}
+		c.daysOfWeek = 1 << uint(day)
+		c.lastOfWeek = true
+		return nil
+	case strings.Contains(field, "#"):
+		parts := strings.SplitN(field, "#", 2)
+		day, err := parseCronValue(parts[0], 1, 7, cronDayNames)
+		if err != nil {
+			return err
+		}
+		nth, err := strconv.Atoi(parts[1])
+		if err != nil || nth < 1 || nth > 5 {
+			return fmt.Errorf("invalid occurrence %q", field)
+		}
+		c.daysOfWeek = 1 << uint(day)
+		c.nthOfWeek = nth
+		return nil
+	}
+
+	var err error
+	c.daysOfWeek, err = parseCronField(field, 1, 7, cronDayNames)
+	return err
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps into a bitset
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		start, end, step, err := parseCronRange(part, min, max, names)
+		if err != nil {
+			return 0, err
+		}
+		for _, v := range expandCronRange(start, end, step, min, max) {
+			set |= 1 << uint(v)
+		}
+	}
+	if set == 0 {
+		return 0, fmt.Errorf("%q matches nothing", field)
+	}
+	return set, nil
+}
+
+func parseCronYears(field string, years []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		start, end, step, err := parseCronRange(part, cronMinYear, cronMaxYear, nil)
+		if err != nil {
+			return err
+		}
+		for _, v := range expandCronRange(start, end, step, cronMinYear, cronMaxYear) {
+			years[v-cronMinYear] = true
+		}
+	}
+	return nil
+}
+
+// parseCronRange parses *, a, a-b, */n, a/n and a-b/n
+func parseCronRange(part string, min, max int, names map[string]int) (int, int, int, error) {
+	step := 1
+	if i := strings.Index(part, "/"); i >= 0 {
+		var err error
+		step, err = strconv.Atoi(part[i+1:])
+		if err != nil || step < 1 {
+			return 0, 0, 0, fmt.Errorf("invalid step %q", part)
+		}
+		part = part[:i]
+		if !strings.Contains(part, "-") && part != "*" {
+			// a/n runs from a to the end of the range
+			start, err := parseCronValue(part, min, max, names)
+			return start, max, step, err
+		}
+	}
+
+	if part == "*" || part == "?" {
+		return min, max, step, nil
+	}
+
+	if i := strings.Index(part, "-"); i >= 0 {
+		start, err := parseCronValue(part[:i], min, max, names)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		end, err := parseCronValue(part[i+1:], min, max, names)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return start, end, step, nil
+	}
+
+	v, err := parseCronValue(part, min, max, names)
+	return v, v, step, err
+}
+
+// expandCronRange lists the values of a range, wrapping around when start is after end, e.g. FRI-MON
+func expandCronRange(start, end, step, min, max int) []int {
+	var values []int
+	size := max - min + 1
+	count := end - start
+	if count < 0 {
+		count += size
+	}
+	for i := 0; i <= count; i += step {
+		values = append(values, min+(start-min+i)%size)
+	}
+	return values
+}
+
+func parseCronValue(value string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[value]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+func lastWeekdayOfMonth(year int, month time.Month, lastDay int) int {
+	switch time.Date(year, month, lastDay, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		return lastDay - 1
+	case time.Sunday:
+		return lastDay - 2
+	}
+	return lastDay
+}
+
+// nearestWeekday is Quartz's W: the weekday closest to day without leaving the month
+func nearestWeekday(year int, month time.Month, day, lastDay int) int {
+	if day > lastDay {
+		return -1
+	}
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3
+		}
+		return day - 1
+	case time.Sunday:
+		if day == lastDay {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// JobFireTimes are the upcoming fire times of a scheduled job
+type JobFireTimes struct {
+	Job      *Job
+	Schedule *CronSchedule
+	Times    []time.Time
+}
+
+// ScheduleCollision is a moment when more than one job is scheduled to start
+type ScheduleCollision struct {
+	Time time.Time
+	Jobs []*Job
+}
+
+// NextFireTimes returns the next n fire times of every job in the project with an enabled schedule.
+// Each job's schedule is evaluated in its own time zone when it has one, otherwise in from's location.
+func (j *Jobs) NextFireTimes(ctx context.Context, project string, from time.Time, n int) ([]*JobFireTimes, error) {
+	jobs, err := j.ListWithContext(ctx, project, &ListJobsInput{ScheduledFilter: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*JobFireTimes
+	for _, job := range jobs {
+		def, err := j.GetJobDefinitionWithContext(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		if def.Schedule == nil || (def.ScheduleEnabled != nil && !*def.ScheduleEnabled) {
+			continue
+		}
+
+		schedule, err := ParseJobSchedule(def.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %v", job.ID, err)
+		}
+
+		loc := from.Location()
+		if def.TimeZone != "" {
+			if loc, err = time.LoadLocation(def.TimeZone); err != nil {
+				return nil, fmt.Errorf("job %s: %v", job.ID, err)
+			}
+		}
+
+		result = append(result, &JobFireTimes{
+			Job:      job,
+			Schedule: schedule,
+			Times:    schedule.NextN(from, n, loc),
+		})
+	}
+	return result, nil
+}
+
+// FindScheduleCollisions returns the moments when more than one of the jobs fires, in time order
+func FindScheduleCollisions(fireTimes []*JobFireTimes) []*ScheduleCollision {
+	byInstant := make(map[int64]*ScheduleCollision)
+	for _, ft := range fireTimes {
+		for _, t := range ft.Times {
+			collision, ok := byInstant[t.Unix()]
+			if !ok {
+				collision = &ScheduleCollision{Time: t}
+				byInstant[t.Unix()] = collision
+			}
+			collision.Jobs = append(collision.Jobs, ft.Job)
+		}
+	}
+
+	var collisions []*ScheduleCollision
+	for _, collision := range byInstant {
+		if len(collision.Jobs) > 1 {
+			collisions = append(collisions, collision)
+		}
+	}
+	sort.Slice(collisions, func(a, b int) bool {
+		return collisions[a].Time.Before(collisions[b].Time)
+	})
+	return collisions
+}
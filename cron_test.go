@@ -0,0 +1,107 @@
+package rundeck_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func TestCronNextFireTimes(t *testing.T) {
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		expr     string
+		expected []string
+	}{
+		{"0 30 2 ? * MON-FRI *", []string{"2026-01-01T02:30:00Z", "2026-01-02T02:30:00Z", "2026-01-05T02:30:00Z"}},
+		{"0 0/15 * * * ?", []string{"2026-01-01T00:15:00Z", "2026-01-01T00:30:00Z", "2026-01-01T00:45:00Z"}},
+		{"0 0 12 L * ?", []string{"2026-01-31T12:00:00Z", "2026-02-28T12:00:00Z", "2026-03-31T12:00:00Z"}},
+		{"0 0 9 ? * 6#3", []string{"2026-01-16T09:00:00Z", "2026-02-20T09:00:00Z", "2026-03-20T09:00:00Z"}},
+		{"0 0 9 15W * ?", []string{"2026-01-15T09:00:00Z", "2026-02-16T09:00:00Z", "2026-03-16T09:00:00Z"}},
+		{"0 0 0 ? * 2L", []string{"2026-01-26T00:00:00Z", "2026-02-23T00:00:00Z", "2026-03-30T00:00:00Z"}},
+		{"0 0 0 1 JAN ? 2027", []string{"2027-01-01T00:00:00Z"}},
+	}
+
+	for _, c := range cases {
+		schedule, err := rundeck.ParseCron(c.expr)
+		if err != nil {
+			t.Errorf("%s: failed to parse: %v\n", c.expr, err)
+			continue
+		}
+
+		times := schedule.NextN(from, 3, nil)
+		if len(times) != len(c.expected) {
+			t.Errorf("%s: expected %d fire times, received %v\n", c.expr, len(c.expected), times)
+			continue
+		}
+		for i, expected := range c.expected {
+			if actual := times[i].Format(time.RFC3339); actual != expected {
+				t.Errorf("%s: fire %d expected %s, received %s\n", c.expr, i+1, expected, actual)
+			}
+		}
+	}
+}
+
+func TestCronTimeZone(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip("time zone database unavailable", err)
+	}
+
+	schedule, err := rundeck.ParseJobSchedule(&rundeck.JobSchedule{Hour: "2", Minute: "30"})
+	if err != nil {
+		t.Fatal("failed to parse schedule", err)
+	}
+
+	// 2:30 does not exist on the day daylight saving time starts
+	times := schedule.NextN(time.Date(2026, time.March, 7, 0, 0, 0, 0, chicago), 2, chicago)
+	if len(times) != 2 || times[0].Format(time.RFC3339) != "2026-03-07T02:30:00-06:00" || times[1].Format(time.RFC3339) != "2026-03-09T02:30:00-05:00" {
+		t.Errorf("unexpected fire times: %v\n", times)
+	}
+}
+
+func TestCronInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"0 0 * * *",
+		"0 0 0 * * MON",
+		"0 60 * ? * *",
+		"0 0 0 ? FOO *",
+		"0 0 0 ? * 2#6",
+	} {
+		if _, err := rundeck.ParseCron(expr); err == nil {
+			t.Errorf("%s: expected a parse error\n", expr)
+		}
+	}
+}
+
+func TestScheduleCollisions(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+	ctx := context.Background()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Collisions"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("Collisions")
+
+	hourly, _ := rundeck.NewJob("hourly").Step(rundeck.Command("uptime")).Schedule("0 0 * ? * *").Build()
+	everyOther, _ := rundeck.NewJob("every-other").Step(rundeck.Command("uptime")).Schedule("0 0 0/2 ? * *").Build()
+	unscheduled, _ := rundeck.NewJob("unscheduled").Step(rundeck.Command("uptime")).Build()
+	if _, err := cli.Jobs().Sync(ctx, "Collisions", []*rundeck.JobDefinition{hourly, everyOther, unscheduled}, nil); err != nil {
+		t.Fatal("failed to create jobs", err)
+	}
+
+	fireTimes, err := cli.Jobs().NextFireTimes(ctx, "Collisions", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), 4)
+	if err != nil {
+		t.Fatal("failed to compute fire times", err)
+	}
+	if len(fireTimes) != 2 {
+		t.Fatalf("expected fire times for the 2 scheduled jobs, received %d\n", len(fireTimes))
+	}
+
+	collisions := rundeck.FindScheduleCollisions(fireTimes)
+	if len(collisions) != 2 || collisions[0].Time.Hour() != 2 || collisions[1].Time.Hour() != 4 || len(collisions[0].Jobs) != 2 {
+		t.Errorf("unexpected collisions: %+v\n", collisions)
+	}
+}
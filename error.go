@@ -2,7 +2,29 @@ package rundeck
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySnippet caps how much of an error response body is retained on an APIError
+const maxErrorBodySnippet = 4096
+
+var (
+	// ErrNotFound is matched by an APIError with a 404 status code
+	ErrNotFound = errors.New("rundeck: not found")
+
+	// ErrUnauthorized is matched by an APIError with a 401 status code
+	ErrUnauthorized = errors.New("rundeck: unauthorized")
+
+	// ErrForbidden is matched by an APIError with a 403 status code
+	ErrForbidden = errors.New("rundeck: forbidden")
+
+	// ErrConflict is matched by an APIError with a 409 status code
+	ErrConflict = errors.New("rundeck: conflict")
 )
 
 // Error is what Rundeck returns given a bad API call
@@ -22,16 +44,95 @@ func (e Error) Error() string {
 	return string(bs)
 }
 
-func makeError(body io.Reader) Error {
-	var err Error
-	goErr := json.NewDecoder(body).Decode(&err)
-	if goErr != nil {
-		return Error{
-			ErrorPresent: true,
-			APIVersion:   -1,
-			ErrorCode:    "decode.failure",
-			Message:      goErr.Error(),
+// APIError is returned when Rundeck responds with an unexpected status code.
+//
+// Use errors.Is with ErrNotFound, ErrUnauthorized, ErrForbidden or ErrConflict to branch on
+// the status code, or errors.As with *APIError to inspect the details.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+
+	// Body is the start of the raw response body, useful when it isn't the expected JSON
+	Body string
+
+	// ErrorCode, Message and APIVersion are populated when the body is a Rundeck error payload
+	ErrorCode  string
+	Message    string
+	APIVersion int
+}
+
+// Error describes the failed request along with the Rundeck error code and message, if any
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("rundeck: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.ErrorCode != "" {
+		msg += ": " + e.ErrorCode
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	} else if e.ErrorCode == "" && e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// Is allows matching an APIError against the sentinel errors by status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// As allows an APIError to be extracted as the Rundeck Error payload
+func (e *APIError) As(target interface{}) bool {
+	if rdErr, ok := target.(*Error); ok {
+		*rdErr = e.rundeckError()
+		return true
+	}
+	return false
+}
+
+func (e *APIError) rundeckError() Error {
+	return Error{
+		ErrorPresent: true,
+		APIVersion:   e.APIVersion,
+		ErrorCode:    e.ErrorCode,
+		Message:      e.Message,
+	}
+}
+
+// makeError consumes and closes the response body, building an APIError from it
+func makeError(res *http.Response) *APIError {
+	defer res.Body.Close()
+
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+	}
+
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		if res.Request.URL != nil {
+			apiErr.URL = res.Request.URL.String()
 		}
 	}
-	return err
+
+	bs, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodySnippet))
+	apiErr.Body = strings.TrimSpace(string(bs))
+
+	var rdErr Error
+	if err := json.Unmarshal(bs, &rdErr); err == nil {
+		apiErr.ErrorCode = rdErr.ErrorCode
+		apiErr.Message = rdErr.Message
+		apiErr.APIVersion = rdErr.APIVersion
+	}
+
+	return apiErr
 }
@@ -0,0 +1,53 @@
+package rundeck_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func TestAPIErrorMatchesSentinels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/24/job/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":true,"apiversion":24,"errorCode":"api.error.item.doesnotexist","message":"Job ID does not exist: missing"}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html>forbidden</html>"))
+		}
+	}))
+	defer server.Close()
+
+	cli := rundeck.NewClient(&rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: "dev-token",
+		ServerURL:        server.URL,
+	})
+
+	_, err := cli.Executions().DeleteExecutions(1)
+	if !errors.Is(err, rundeck.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got: %v\n", err)
+	}
+
+	var apiErr *rundeck.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got: %T\n", err)
+	}
+	if apiErr.Body != "<html>forbidden</html>" || apiErr.Method != http.MethodDelete {
+		t.Errorf("unexpected error details: %+v\n", apiErr)
+	}
+
+	err = cli.Jobs().DeleteDefinition("missing")
+	if !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got: %v\n", err)
+	}
+
+	var rdErr rundeck.Error
+	if !errors.As(err, &rdErr) || rdErr.ErrorCode != "api.error.item.doesnotexist" {
+		t.Errorf("expected the rundeck error payload, got: %+v\n", rdErr)
+	}
+}
@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -176,7 +177,7 @@ type ExecutionsOutputInput struct {
 	StepContext string
 	Offset      int
 	LastLines   int
-	LastMod     *time.Time
+	LastMod     *time.Time // sent in unix milliseconds, the unit of ExecutionsOutputResponse.LastModified
 	Compacted   bool
 }
 
@@ -259,7 +260,17 @@ func (c *Client) Executions() *Executions {
 
 // GetExecutionsForAJob returns the executions pertaining to a certain job
 func (e *Executions) GetExecutionsForAJob(id int, status *string, paging *PagingInfo) (*ExecutionsResponse, error) {
-	rawURL := fmt.Sprintf("%s/job/%d/executions", e.c.RundeckAddr, id)
+	return e.GetExecutionsForAJobWithContext(context.Background(), id, status, paging)
+}
+
+// GetExecutionsForAJobWithContext is the same as GetExecutionsForAJob with the addition of the ability to pass a context.
+func (e *Executions) GetExecutionsForAJobWithContext(ctx context.Context, id int, status *string, paging *PagingInfo) (*ExecutionsResponse, error) {
+	return e.executionsForJob(ctx, strconv.Itoa(id), status, paging)
+}
+
+// executionsForJob lists a page of the executions of the job with the given id, a uuid on any recent Rundeck
+func (e *Executions) executionsForJob(ctx context.Context, id string, status *string, paging *PagingInfo) (*ExecutionsResponse, error) {
+	rawURL := fmt.Sprintf("%s/job/%s/executions", e.c.RundeckAddr, url.PathEscape(id))
 
 	uri, err := url.Parse(rawURL)
 	if err != nil {
@@ -272,19 +283,11 @@ func (e *Executions) GetExecutionsForAJob(id int, status *string, paging *Paging
 		query.Add("status", stringValue(status))
 	}
 
-	if paging != nil {
-		if paging.Max != 0 {
-			query.Add("max", strconv.FormatInt(int64(paging.Max), 10))
-		}
-
-		if paging.Offset != 0 {
-			query.Add("offset", strconv.FormatInt(int64(paging.Offset), 10))
-		}
-	}
+	encodePagingInfo(query, paging)
 
 	uri.RawQuery = query.Encode()
 
-	res, err := e.c.checkResponseOK(e.c.get(uri.String()))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -296,9 +299,14 @@ func (e *Executions) GetExecutionsForAJob(id int, status *string, paging *Paging
 
 // DeleteExecutions deletes all executions for a job
 func (e *Executions) DeleteExecutions(id int) (*DeleteExecutionsResponse, error) {
+	return e.DeleteExecutionsWithContext(context.Background(), id)
+}
+
+// DeleteExecutionsWithContext is the same as DeleteExecutions with the addition of the ability to pass a context.
+func (e *Executions) DeleteExecutionsWithContext(ctx context.Context, id int) (*DeleteExecutionsResponse, error) {
 	rawURL := fmt.Sprintf("%s/job/%d/executions", e.c.RundeckAddr, id)
 
-	res, err := e.c.checkResponseOK(e.c.delete(rawURL, nil))
+	res, err := e.c.checkResponseOK(e.c.delete(ctx, rawURL, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -310,9 +318,14 @@ func (e *Executions) DeleteExecutions(id int) (*DeleteExecutionsResponse, error)
 
 // ListRunningExecutions returns running executions for the specified project ("*" for all projects)
 func (e *Executions) ListRunningExecutions(project string) (*ExecutionsResponse, error) {
+	return e.ListRunningExecutionsWithContext(context.Background(), project)
+}
+
+// ListRunningExecutionsWithContext is the same as ListRunningExecutions with the addition of the ability to pass a context.
+func (e *Executions) ListRunningExecutionsWithContext(ctx context.Context, project string) (*ExecutionsResponse, error) {
 	rawURL := e.c.RundeckAddr + "/project/" + project + "/executions/running"
 
-	res, err := e.c.checkResponseOK(e.c.get(rawURL))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -324,9 +337,14 @@ func (e *Executions) ListRunningExecutions(project string) (*ExecutionsResponse,
 
 // Info returns information about the specific execution
 func (e *Executions) Info(id int) (*Execution, error) {
+	return e.InfoWithContext(context.Background(), id)
+}
+
+// InfoWithContext is the same as Info with the addition of the ability to pass a context.
+func (e *Executions) InfoWithContext(ctx context.Context, id int) (*Execution, error) {
 	rawURL := e.c.RundeckAddr + "/execution/" + strconv.FormatInt(int64(id), 10)
 
-	res, err := e.c.checkResponseOK(e.c.get(rawURL))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -338,9 +356,14 @@ func (e *Executions) Info(id int) (*Execution, error) {
 
 // ListInputFiles lists input ifle sused for an execution
 func (e *Executions) ListInputFiles(id int) (*UploadedFilesResponse, error) {
+	return e.ListInputFilesWithContext(context.Background(), id)
+}
+
+// ListInputFilesWithContext is the same as ListInputFiles with the addition of the ability to pass a context.
+func (e *Executions) ListInputFilesWithContext(ctx context.Context, id int) (*UploadedFilesResponse, error) {
 	rawURL := e.c.RundeckAddr + "/execution/" + strconv.FormatInt(int64(id), 10) + "/input/files"
 
-	res, err := e.c.checkResponseOK(e.c.get(rawURL))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -352,9 +375,14 @@ func (e *Executions) ListInputFiles(id int) (*UploadedFilesResponse, error) {
 
 // Delete deletes an execution by id
 func (e *Executions) Delete(id int) error {
+	return e.DeleteWithContext(context.Background(), id)
+}
+
+// DeleteWithContext is the same as Delete with the addition of the ability to pass a context.
+func (e *Executions) DeleteWithContext(ctx context.Context, id int) error {
 	rawURL := e.c.RundeckAddr + "/execution/" + strconv.FormatInt(int64(id), 10)
 
-	res, err := e.c.checkResponseNoContent(e.c.delete(rawURL, nil))
+	res, err := e.c.checkResponseNoContent(e.c.delete(ctx, rawURL, nil))
 	if err != nil {
 		return err
 	}
@@ -365,6 +393,11 @@ func (e *Executions) Delete(id int) error {
 
 // BulkDelete deletes a set of executions by their ids
 func (e *Executions) BulkDelete(ids []int) (*DeleteExecutionsResponse, error) {
+	return e.BulkDeleteWithContext(context.Background(), ids)
+}
+
+// BulkDeleteWithContext is the same as BulkDelete with the addition of the ability to pass a context.
+func (e *Executions) BulkDeleteWithContext(ctx context.Context, ids []int) (*DeleteExecutionsResponse, error) {
 	rawURL := e.c.RundeckAddr + "/executions/delete"
 
 	bs, err := json.Marshal(ids)
@@ -372,7 +405,7 @@ func (e *Executions) BulkDelete(ids []int) (*DeleteExecutionsResponse, error) {
 		return nil, err
 	}
 
-	res, err := e.c.checkResponseOK(e.c.delete(rawURL, bytes.NewReader(bs)))
+	res, err := e.c.checkResponseOK(e.c.delete(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -384,6 +417,11 @@ func (e *Executions) BulkDelete(ids []int) (*DeleteExecutionsResponse, error) {
 
 // Query queries for executions based on job or execution details
 func (e *Executions) Query(project string, input *ExecutionQueryInput) (*ExecutionsResponse, error) {
+	return e.QueryWithContext(context.Background(), project, input)
+}
+
+// QueryWithContext is the same as Query with the addition of the ability to pass a context.
+func (e *Executions) QueryWithContext(ctx context.Context, project string, input *ExecutionQueryInput) (*ExecutionsResponse, error) {
 	rawURL := e.c.RundeckAddr + "/project/" + project + "/executions"
 
 	uri, err := url.Parse(rawURL)
@@ -505,7 +543,7 @@ func (e *Executions) Query(project string, input *ExecutionQueryInput) (*Executi
 
 	uri.RawQuery = query.Encode()
 
-	res, err := e.c.checkResponseOK(e.c.get(uri.String()))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -517,9 +555,14 @@ func (e *Executions) Query(project string, input *ExecutionQueryInput) (*Executi
 
 // State gets detailed about the node and step state of an execution by ID. The execution can be currently running or completed.
 func (e *Executions) State(id int) (*ExecutionStateResponse, error) {
+	return e.StateWithContext(context.Background(), id)
+}
+
+// StateWithContext is the same as State with the addition of the ability to pass a context.
+func (e *Executions) StateWithContext(ctx context.Context, id int) (*ExecutionStateResponse, error) {
 	rawURL := fmt.Sprintf("%s/execution/%d/state", e.c.RundeckAddr, id)
 
-	res, err := e.c.checkResponseOK(e.c.get(rawURL))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -533,6 +576,11 @@ func (e *Executions) State(id int) (*ExecutionStateResponse, error) {
 // The execution can be currently running or may have already completed.
 // Output can be filtered down to a specific node or workflow step.
 func (e *Executions) Output(id int, input *ExecutionsOutputInput) (*ExecutionsOutputResponse, error) {
+	return e.OutputWithContext(context.Background(), id, input)
+}
+
+// OutputWithContext is the same as Output with the addition of the ability to pass a context.
+func (e *Executions) OutputWithContext(ctx context.Context, id int, input *ExecutionsOutputInput) (*ExecutionsOutputResponse, error) {
 	rawURL := fmt.Sprintf("%s/execution/%d/output", e.c.RundeckAddr, id)
 
 	uri, err := url.Parse(rawURL)
@@ -560,7 +608,7 @@ func (e *Executions) Output(id int, input *ExecutionsOutputInput) (*ExecutionsOu
 		}
 
 		if input.LastMod != nil {
-			query.Add("lastmod", strconv.FormatInt(int64(input.LastMod.Unix()), 10))
+			query.Add("lastmod", strconv.FormatInt(input.LastMod.UnixNano()/int64(time.Millisecond), 10))
 		}
 
 		if input.Offset != 0 {
@@ -570,7 +618,7 @@ func (e *Executions) Output(id int, input *ExecutionsOutputInput) (*ExecutionsOu
 
 	uri.RawQuery = query.Encode()
 
-	res, err := e.c.checkResponseOK(e.c.get(uri.String()))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -582,6 +630,11 @@ func (e *Executions) Output(id int, input *ExecutionsOutputInput) (*ExecutionsOu
 
 // OutputWithState get the metadata associated with workflow step state changes along with the log output, optionally excluding log output.
 func (e *Executions) OutputWithState(id int, stateOnly bool) (*ExecutionsOutputResponse, error) {
+	return e.OutputWithStateWithContext(context.Background(), id, stateOnly)
+}
+
+// OutputWithStateWithContext is the same as OutputWithState with the addition of the ability to pass a context.
+func (e *Executions) OutputWithStateWithContext(ctx context.Context, id int, stateOnly bool) (*ExecutionsOutputResponse, error) {
 	rawURL := fmt.Sprintf("%s/execution/%d/output/state", e.c.RundeckAddr, id)
 
 	uri, err := url.Parse(rawURL)
@@ -597,7 +650,7 @@ func (e *Executions) OutputWithState(id int, stateOnly bool) (*ExecutionsOutputR
 
 	uri.RawQuery = query.Encode()
 
-	res, err := e.c.checkResponseOK(e.c.get(uri.String()))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -609,6 +662,11 @@ func (e *Executions) OutputWithState(id int, stateOnly bool) (*ExecutionsOutputR
 
 // Abort aborts a running execution by id
 func (e *Executions) Abort(id int, asUser *string) (*AbortExecutionResponse, error) {
+	return e.AbortWithContext(context.Background(), id, asUser)
+}
+
+// AbortWithContext is the same as Abort with the addition of the ability to pass a context.
+func (e *Executions) AbortWithContext(ctx context.Context, id int, asUser *string) (*AbortExecutionResponse, error) {
 	rawURL := fmt.Sprintf("%s/execution/%d/abort", e.c.RundeckAddr, id)
 
 	uri, err := url.Parse(rawURL)
@@ -624,7 +682,7 @@ func (e *Executions) Abort(id int, asUser *string) (*AbortExecutionResponse, err
 
 	uri.RawQuery = query.Encode()
 
-	res, err := e.c.checkResponseOK(e.c.get(uri.String()))
+	res, err := e.c.checkResponseOK(e.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,120 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultFollowInterval = time.Second
+
+// FollowInput configures how Executions.Follow tails the execution log
+type FollowInput struct {
+	// Node and StepContext filter the output the same way as ExecutionsOutputInput
+	Node        string
+	StepContext string
+
+	// Interval is the delay between polls when no new output is available.  Defaults to 1s.
+	Interval time.Duration
+
+	// ShowNode, ShowStep and ShowLevel prefix each line with the entry's node, step context and level
+	ShowNode  bool
+	ShowStep  bool
+	ShowLevel bool
+
+	// Format overrides how an entry is written.  The returned string is written as is.
+	Format func(*LogEntry) string
+}
+
+// Follow tails the output of an execution into w until the execution completes and all of its
+// output has been written.  The last output response is returned so its ExecutionState can be inspected.
+func (e *Executions) Follow(ctx context.Context, id int, w io.Writer, input *FollowInput) (*ExecutionsOutputResponse, error) {
+	if input == nil {
+		input = &FollowInput{}
+	}
+
+	interval := input.Interval
+	if interval <= 0 {
+		interval = defaultFollowInterval
+	}
+
+	outputInput := ExecutionsOutputInput{
+		Node:        input.Node,
+		StepContext: input.StepContext,
+	}
+
+	for {
+		output, err := e.OutputWithContext(ctx, id, &outputInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range output.Entries {
+			if entry.Type != "" && entry.Type != LogEntryTypeLog {
+				continue
+			}
+			if _, err := io.WriteString(w, input.format(entry)); err != nil {
+				return output, err
+			}
+		}
+
+		if output.Offset != "" {
+			offset, err := strconv.Atoi(output.Offset)
+			if err != nil {
+				return output, fmt.Errorf("invalid output offset %q: %v", output.Offset, err)
+			}
+			outputInput.Offset = offset
+		}
+
+		if ms, err := strconv.ParseInt(output.LastModified, 10, 64); err == nil && ms > 0 {
+			lastMod := time.Unix(0, ms*int64(time.Millisecond))
+			outputInput.LastMod = &lastMod
+		}
+
+		if output.ExecCompleted && (output.Completed || len(output.Entries) == 0) {
+			return output, nil
+		}
+
+		// more output may already be buffered, so only back off when nothing new arrived
+		if !output.Unmodified && len(output.Entries) > 0 {
+			continue
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return output, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (input *FollowInput) format(entry *LogEntry) string {
+	if input.Format != nil {
+		return input.Format(entry)
+	}
+
+	var prefix []string
+	if input.ShowNode && entry.Node != "" {
+		prefix = append(prefix, "["+entry.Node+"]")
+	}
+	if input.ShowStep && entry.StepContext != "" {
+		prefix = append(prefix, "["+entry.StepContext+"]")
+	}
+	if input.ShowLevel && entry.Level != "" {
+		prefix = append(prefix, "["+string(entry.Level)+"]")
+	}
+
+	line := entry.Log
+	if len(prefix) > 0 {
+		line = strings.Join(prefix, " ") + " " + line
+	}
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	return line
+}
@@ -0,0 +1,89 @@
+package rundeck_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestFollowExecutionOutput(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Test"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	server.SetExecutionScript(rundecktest.ExecutionScript{
+		Statuses: []rundeck.ExecutionStatus{
+			rundeck.ExecutionStatusRunning,
+			rundeck.ExecutionStatusRunning,
+			rundeck.ExecutionStatusRunning,
+			rundeck.ExecutionStatusSucceeded,
+		},
+		Output: []*rundeck.LogEntry{
+			{Log: "one", Node: "web1", StepContext: "1", Level: rundeck.JobLogLevelInfo},
+			{Log: "two", Node: "web2", StepContext: "1", Level: rundeck.JobLogLevelInfo},
+			{Log: "three", Node: "web1", StepContext: "2", Level: rundeck.JobLogLevelError},
+			{Log: "four", Node: "web1", StepContext: "2", Level: rundeck.JobLogLevelInfo},
+		},
+	})
+
+	resp, err := cli.Adhoc().RunCommandString(&rundeck.AdhocCommandStringInput{
+		Exec:         "pwd",
+		AdhocOptions: rundeck.AdhocOptions{Project: "Test"},
+	})
+	if err != nil {
+		t.Fatal("failed to run adhoc command", err)
+	}
+
+	var buf bytes.Buffer
+	output, err := cli.Executions().Follow(context.Background(), resp.Execution.ID, &buf, &rundeck.FollowInput{
+		Node:      "web1",
+		Interval:  time.Millisecond,
+		ShowStep:  true,
+		ShowLevel: true,
+	})
+	if err != nil {
+		t.Fatal("failed to follow output", err)
+	}
+
+	expected := "[1] [INFO] one\n[2] [ERROR] three\n[2] [INFO] four\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output.  expected: %q\tactual: %q\n", expected, buf.String())
+	}
+
+	if !output.ExecCompleted || output.ExecutionState != rundeck.ExecutionStateSucceeded {
+		t.Errorf("unexpected final output response: %+v\n", output)
+	}
+}
+
+func TestOutputLastModIsMilliseconds(t *testing.T) {
+	var lastmod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastmod = r.URL.Query().Get("lastmod")
+		w.Write([]byte(`{"id":"1","unmodified":true}`))
+	}))
+	defer server.Close()
+
+	cli := rundeck.NewClient(&rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: "dev-token",
+		ServerURL:        server.URL,
+	})
+
+	since := time.Date(2026, time.January, 1, 0, 0, 0, 250*int(time.Millisecond), time.UTC)
+	if _, err := cli.Executions().Output(1, &rundeck.ExecutionsOutputInput{LastMod: &since}); err != nil {
+		t.Fatal("failed to get output", err)
+	}
+	if lastmod != "1767225600250" {
+		t.Errorf("expected lastmod in milliseconds, received %s\n", lastmod)
+	}
+}
@@ -0,0 +1,277 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleForecastInput are the parameters for ClusterScheduler.Forecast
+type ScheduleForecastInput struct {
+	// From and To bound the forecast window.  From defaults to now and To to 24 hours after From.
+	From time.Time
+	To   time.Time
+
+	// SlotSize is the resolution of the forecast, 5 minutes by default
+	SlotSize time.Duration
+
+	// ServerUUIDs are the cluster members to forecast.  By default each member owning the schedule of a job
+	// in any project is forecast.
+	ServerUUIDs []string
+
+	// DefaultDuration is used for jobs that have no average duration yet, 1 minute by default
+	DefaultDuration time.Duration
+
+	// ThreadPoolSize is the scheduler thread pool size of every member.  By default System.Info gives the
+	// size of the server the client talks to, and the size of other members is unknown.
+	ThreadPoolSize int
+}
+
+// ScheduleForecast is the projected concurrent job load of each cluster member
+type ScheduleForecast struct {
+	From     time.Time
+	To       time.Time
+	SlotSize time.Duration
+	Nodes    []*NodeScheduleForecast
+}
+
+// NodeScheduleForecast is the projected load of a single cluster member
+type NodeScheduleForecast struct {
+	ServerNodeUUID string
+	// ThreadPoolSize is the member's scheduler thread pool size, zero when it is unknown
+	ThreadPoolSize int
+	Jobs           []*Job
+	Slots          []*ScheduleSlot
+	Peak           int
+	// Overloaded are the slots where concurrency exceeds the thread pool size.  It is empty when the
+	// thread pool size is unknown.
+	Overloaded []*ScheduleSlot
+}
+
+// ScheduleSlot is the jobs expected to be running during a slot of the forecast
+type ScheduleSlot struct {
+	Start time.Time
+	// Concurrency counts every run overlapping the slot, so a job allowing multiple executions whose runs
+	// overlap counts once for each of them
+	Concurrency int
+	Jobs        []*Job
+}
+
+// Overloaded reports whether any node is expected to exceed the thread pool size
+func (f *ScheduleForecast) Overloaded() bool {
+	for _, node := range f.Nodes {
+		if len(node.Overloaded) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the forecast, listing every overloaded slot
+func (f *ScheduleForecast) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Schedule forecast %s to %s (%s slots)\n", f.From.Format(time.RFC3339), f.To.Format(time.RFC3339), f.SlotSize)
+
+	for _, node := range f.Nodes {
+		poolSize := "unknown"
+		if node.ThreadPoolSize > 0 {
+			poolSize = strconv.Itoa(node.ThreadPoolSize)
+		}
+		fmt.Fprintf(&b, "node %s: %d scheduled job(s), peak concurrency %d, thread pool size %s", node.ServerNodeUUID, len(node.Jobs), node.Peak, poolSize)
+		if len(node.Overloaded) > 0 {
+			fmt.Fprintf(&b, ", %d overloaded slot(s)", len(node.Overloaded))
+		}
+		b.WriteString("\n")
+
+		for _, slot := range node.Overloaded {
+			var names []string
+			for _, job := range slot.Jobs {
+				names = append(names, joinGroupName(job.Group, job.Name))
+			}
+			fmt.Fprintf(&b, "  ! %s %d concurrent: %s\n", slot.Start.Format(time.RFC3339), slot.Concurrency, strings.Join(names, ", "))
+		}
+	}
+	return b.String()
+}
+
+// Forecast projects the concurrent job load of each cluster member over a window, using the schedule
+// of every job the member owns and each job's average duration.  Slots where the expected concurrency
+// exceeds the member's scheduler thread pool size are flagged.
+func (cs *ClusterScheduler) Forecast(ctx context.Context, input *ScheduleForecastInput) (*ScheduleForecast, error) {
+	var in ScheduleForecastInput
+	if input != nil {
+		in = *input
+	}
+	if in.From.IsZero() {
+		in.From = time.Now()
+	}
+	if in.To.IsZero() {
+		in.To = in.From.Add(24 * time.Hour)
+	}
+	if !in.To.After(in.From) {
+		return nil, fmt.Errorf("forecast window must end after it starts")
+	}
+	if in.SlotSize <= 0 {
+		in.SlotSize = 5 * time.Minute
+	}
+	if in.DefaultDuration <= 0 {
+		in.DefaultDuration = time.Minute
+	}
+
+	info, err := cs.c.System().InfoWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	local := info.System.Rundeck.ServerUUID
+
+	if len(in.ServerUUIDs) == 0 {
+		if in.ServerUUIDs, err = cs.scheduleOwners(ctx, local); err != nil {
+			return nil, err
+		}
+	}
+
+	forecast := &ScheduleForecast{
+		From:     in.From,
+		To:       in.To,
+		SlotSize: in.SlotSize,
+	}
+
+	for _, uuid := range in.ServerUUIDs {
+		// the server the client talks to lists its own jobs even outside of cluster mode, where they have no owner
+		owner := &uuid
+		if uuid == local {
+			owner = nil
+		}
+		jobs, err := cs.ListScheduledJobsWithContext(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+
+		// only the thread pool of the server the client talks to is known
+		poolSize := in.ThreadPoolSize
+		if poolSize == 0 && uuid == local {
+			poolSize = info.System.Stats.Scheduler.ThreadPoolSize
+		}
+
+		node, err := cs.forecastNode(ctx, uuid, jobs, poolSize, &in)
+		if err != nil {
+			return nil, err
+		}
+		forecast.Nodes = append(forecast.Nodes, node)
+	}
+
+	return forecast, nil
+}
+
+// scheduleOwners returns the uuids of the servers owning the schedule of a job in any project.  Jobs without
+// an owner, as reported outside of cluster mode, belong to the given server.
+func (cs *ClusterScheduler) scheduleOwners(ctx context.Context, local string) ([]string, error) {
+	projects, err := cs.c.Projects().ListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var owners []string
+	for _, project := range projects {
+		jobs, err := cs.c.Jobs().ListWithContext(ctx, project.Name, &ListJobsInput{ScheduledFilter: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			uuid := job.ServerNodeUUID
+			if uuid == "" {
+				uuid = local
+			}
+			if !seen[uuid] {
+				seen[uuid] = true
+				owners = append(owners, uuid)
+			}
+		}
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
+
+func (cs *ClusterScheduler) forecastNode(ctx context.Context, uuid string, jobs []*Job, poolSize int, in *ScheduleForecastInput) (*NodeScheduleForecast, error) {
+	node := &NodeScheduleForecast{ServerNodeUUID: uuid, ThreadPoolSize: poolSize, Jobs: jobs}
+	for start := in.From; start.Before(in.To); start = start.Add(in.SlotSize) {
+		node.Slots = append(node.Slots, &ScheduleSlot{Start: start})
+	}
+
+	for _, job := range jobs {
+		def, err := cs.c.Jobs().GetJobDefinitionWithContext(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		if def.Schedule == nil || (def.ScheduleEnabled != nil && !*def.ScheduleEnabled) {
+			continue
+		}
+
+		schedule, err := ParseJobSchedule(def.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %v", job.ID, err)
+		}
+
+		from := in.From
+		if def.TimeZone != "" {
+			loc, err := time.LoadLocation(def.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("job %s: %v", job.ID, err)
+			}
+			from = from.In(loc)
+		}
+
+		duration := time.Duration(job.AverageDuration) * time.Millisecond
+		if duration <= 0 {
+			duration = in.DefaultDuration
+		}
+
+		// include runs that started before the window but are still going when it opens
+		for _, fire := range schedule.Between(from.Add(-duration), in.To) {
+			first := int(fire.Sub(in.From) / in.SlotSize)
+			if fire.Before(in.From) {
+				first = 0
+			}
+			end := fire.Add(duration)
+			for i := first; i < len(node.Slots) && end.After(node.Slots[i].Start); i++ {
+				slot := node.Slots[i]
+				listed := containsJob(slot.Jobs, job)
+				if !listed {
+					slot.Jobs = append(slot.Jobs, job)
+				}
+				// runs of a job allowing multiple executions overlap, each taking a thread, while a job that
+				// doesn't allow them only ever occupies one
+				if !listed || def.MultipleExecutions {
+					slot.Concurrency++
+				}
+			}
+		}
+	}
+
+	for _, slot := range node.Slots {
+		sort.Slice(slot.Jobs, func(a, b int) bool {
+			return joinGroupName(slot.Jobs[a].Group, slot.Jobs[a].Name) < joinGroupName(slot.Jobs[b].Group, slot.Jobs[b].Name)
+		})
+		if slot.Concurrency > node.Peak {
+			node.Peak = slot.Concurrency
+		}
+		if poolSize > 0 && slot.Concurrency > poolSize {
+			node.Overloaded = append(node.Overloaded, slot)
+		}
+	}
+
+	return node, nil
+}
+
+func containsJob(jobs []*Job, job *Job) bool {
+	for _, j := range jobs {
+		if j.ID == job.ID {
+			return true
+		}
+	}
+	return false
+}
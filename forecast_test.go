@@ -0,0 +1,188 @@
+package rundeck_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestScheduleForecast(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+	ctx := context.Background()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Forecast"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("Forecast")
+
+	info, err := cli.System().Info()
+	if err != nil {
+		t.Fatal("failed to get system info", err)
+	}
+	poolSize := info.System.Stats.Scheduler.ThreadPoolSize
+
+	// one more nightly job than there are scheduler threads, plus a quiet hourly job
+	var defs []*rundeck.JobDefinition
+	for i := 0; i <= poolSize; i++ {
+		def, err := rundeck.NewJob(fmt.Sprintf("nightly-%02d", i)).Step(rundeck.Command("backup.sh")).Schedule("0 0 2 ? * *").Build()
+		if err != nil {
+			t.Fatal("failed to build job", err)
+		}
+		defs = append(defs, def)
+	}
+	hourly, _ := rundeck.NewJob("hourly").Step(rundeck.Command("uptime")).Schedule("0 30 * ? * *").Build()
+	defs = append(defs, hourly)
+
+	if _, err := cli.Jobs().Sync(ctx, "Forecast", defs, nil); err != nil {
+		t.Fatal("failed to create jobs", err)
+	}
+
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := cli.ClusterScheduler().Forecast(ctx, &rundeck.ScheduleForecastInput{
+		From:            from,
+		To:              from.Add(6 * time.Hour),
+		SlotSize:        15 * time.Minute,
+		DefaultDuration: 20 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal("failed to forecast", err)
+	}
+
+	if len(forecast.Nodes) != 1 || forecast.Nodes[0].ThreadPoolSize != poolSize {
+		t.Fatalf("unexpected forecast: %+v\n", forecast)
+	}
+	node := forecast.Nodes[0]
+	if node.Peak != poolSize+1 || !forecast.Overloaded() {
+		t.Errorf("expected a peak of %d, received %d\n", poolSize+1, node.Peak)
+	}
+
+	// the nightly jobs run from 2:00 to 2:20, spanning two 15 minute slots
+	if len(node.Overloaded) != 2 || !node.Overloaded[0].Start.Equal(from.Add(2*time.Hour)) {
+		t.Errorf("unexpected overloaded slots:\n%s", forecast)
+	}
+	if !strings.Contains(forecast.String(), fmt.Sprintf("! 2026-01-01T02:00:00Z %d concurrent", poolSize+1)) {
+		t.Errorf("unexpected report:\n%s", forecast)
+	}
+}
+
+func TestScheduleForecastCluster(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	second := server.AddClusterMember("22222222-2222-2222-2222-222222222222")
+
+	cli := server.Client()
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Cluster"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	info, err := cli.System().Info()
+	if err != nil {
+		t.Fatal("failed to get system info", err)
+	}
+	poolSize := info.System.Stats.Scheduler.ThreadPoolSize
+
+	// the nightly jobs are owned by the second member, the hourly one by the server the client talks to
+	var nightly []*rundeck.JobDefinition
+	for i := 0; i <= poolSize; i++ {
+		def, _ := rundeck.NewJob(fmt.Sprintf("nightly-%02d", i)).Step(rundeck.Command("backup.sh")).Schedule("0 0 2 ? * *").Build()
+		nightly = append(nightly, def)
+	}
+	if _, err := second.Client().Jobs().Sync(ctx, "Cluster", nightly, nil); err != nil {
+		t.Fatal("failed to create jobs", err)
+	}
+	hourly, _ := rundeck.NewJob("hourly").Step(rundeck.Command("uptime")).Schedule("0 30 * ? * *").Build()
+	if _, err := cli.Jobs().Sync(ctx, "Cluster", []*rundeck.JobDefinition{hourly}, nil); err != nil {
+		t.Fatal("failed to create job", err)
+	}
+
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := cli.ClusterScheduler().Forecast(ctx, &rundeck.ScheduleForecastInput{
+		From:            from,
+		To:              from.Add(6 * time.Hour),
+		SlotSize:        15 * time.Minute,
+		DefaultDuration: 20 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal("failed to forecast", err)
+	}
+
+	if len(forecast.Nodes) != 2 {
+		t.Fatalf("expected a row for each member owning jobs:\n%s", forecast)
+	}
+	// only the thread pool of the server the client talks to is known, so the other member can't be flagged
+	member, local := forecast.Nodes[0], forecast.Nodes[1]
+	if member.ServerNodeUUID != second.UUID || len(member.Jobs) != poolSize+1 || member.Peak != poolSize+1 || member.ThreadPoolSize != 0 || len(member.Overloaded) != 0 {
+		t.Errorf("unexpected forecast for %s:\n%s", second.UUID, forecast)
+	}
+	if local.ServerNodeUUID != rundecktest.ServerUUID || len(local.Jobs) != 1 || local.Peak != 1 || local.ThreadPoolSize != poolSize || len(local.Overloaded) != 0 {
+		t.Errorf("unexpected forecast for %s:\n%s", rundecktest.ServerUUID, forecast)
+	}
+	if !strings.Contains(forecast.String(), "node "+second.UUID+": "+fmt.Sprint(poolSize+1)+" scheduled job(s), peak concurrency "+fmt.Sprint(poolSize+1)+", thread pool size unknown") {
+		t.Errorf("expected the unknown thread pool size to be reported:\n%s", forecast)
+	}
+
+	// naming a member forecasts only that one, and a given thread pool size applies to it
+	forecast, err = cli.ClusterScheduler().Forecast(ctx, &rundeck.ScheduleForecastInput{
+		From:            from,
+		To:              from.Add(6 * time.Hour),
+		SlotSize:        15 * time.Minute,
+		DefaultDuration: 20 * time.Minute,
+		ServerUUIDs:     []string{second.UUID},
+		ThreadPoolSize:  poolSize,
+	})
+	if err != nil {
+		t.Fatal("failed to forecast", err)
+	}
+	if len(forecast.Nodes) != 1 || len(forecast.Nodes[0].Jobs) != poolSize+1 || len(forecast.Nodes[0].Overloaded) != 2 {
+		t.Errorf("unexpected forecast:\n%s", forecast)
+	}
+}
+
+func TestScheduleForecastMultipleExecutions(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	cli := server.Client()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Overlap"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	// both jobs fire every minute and run for five
+	overlapping, _ := rundeck.NewJob("overlapping").MultipleExecutions(true).Step(rundeck.Command("poll.sh")).Schedule("0 * * ? * *").Build()
+	single, _ := rundeck.NewJob("single").Step(rundeck.Command("poll.sh")).Schedule("0 * * ? * *").Build()
+	imported, err := cli.Jobs().Sync(ctx, "Overlap", []*rundeck.JobDefinition{overlapping, single}, nil)
+	if err != nil {
+		t.Fatal("failed to create jobs", err)
+	}
+	for _, job := range imported.Imported.Succeeded {
+		server.SetJobAverageDuration(job.ID, 5*time.Minute)
+	}
+
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := cli.ClusterScheduler().Forecast(ctx, &rundeck.ScheduleForecastInput{
+		From:     from,
+		To:       from.Add(10 * time.Minute),
+		SlotSize: time.Minute,
+	})
+	if err != nil {
+		t.Fatal("failed to forecast", err)
+	}
+
+	// five runs of the overlapping job are in flight in every minute, next to the single run of the other
+	node := forecast.Nodes[0]
+	for _, slot := range node.Slots {
+		if slot.Concurrency != 6 || len(slot.Jobs) != 2 {
+			t.Errorf("expected 6 concurrent runs of 2 jobs at %s, received %d of %d\n", slot.Start.Format(time.RFC3339), slot.Concurrency, len(slot.Jobs))
+		}
+	}
+	if node.Peak != 6 {
+		t.Errorf("expected a peak of 6, received %d\n", node.Peak)
+	}
+}
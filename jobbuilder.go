@@ -0,0 +1,350 @@
+package rundeck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JobValidationError lists every problem found while validating a job definition
+type JobValidationError struct {
+	Job      string
+	Problems []string
+}
+
+func (e *JobValidationError) Error() string {
+	return fmt.Sprintf("invalid job definition %q: %s", e.Job, strings.Join(e.Problems, "; "))
+}
+
+// JobBuilder builds a JobDefinition one piece at a time.
+//
+//	input, err := rundeck.NewJob("deploy").
+//		Group("ops/web").
+//		Option(&rundeck.JobOption{Name: "env", Required: true}).
+//		Step(rundeck.Command("deploy.sh ${option.env}")).
+//		Schedule("0 30 2 ? * MON-FRI *").
+//		NodeFilter("tags: web").
+//		ImportJobsInput()
+type JobBuilder struct {
+	def *JobDefinition
+}
+
+// NewJob starts building a job with the given name
+func NewJob(name string) *JobBuilder {
+	return &JobBuilder{def: &JobDefinition{Name: name}}
+}
+
+// UUID sets the job uuid, allowing the job to be updated in place on later imports
+func (b *JobBuilder) UUID(uuid string) *JobBuilder {
+	b.def.UUID = uuid
+	return b
+}
+
+// Group sets the job group, e.g. ops/web
+func (b *JobBuilder) Group(group string) *JobBuilder {
+	b.def.Group = group
+	return b
+}
+
+// Description sets the job description
+func (b *JobBuilder) Description(description string) *JobBuilder {
+	b.def.Description = description
+	return b
+}
+
+// LogLevel sets the job log level
+func (b *JobBuilder) LogLevel(level LogLevel) *JobBuilder {
+	b.def.LogLevel = level
+	return b
+}
+
+// ExecutionEnabled enables or disables executions of the job
+func (b *JobBuilder) ExecutionEnabled(enabled bool) *JobBuilder {
+	b.def.ExecutionEnabled = &enabled
+	return b
+}
+
+// ScheduleEnabled enables or disables the job schedule
+func (b *JobBuilder) ScheduleEnabled(enabled bool) *JobBuilder {
+	b.def.ScheduleEnabled = &enabled
+	return b
+}
+
+// MultipleExecutions allows the job to run more than once at a time
+func (b *JobBuilder) MultipleExecutions(allowed bool) *JobBuilder {
+	b.def.MultipleExecutions = allowed
+	return b
+}
+
+// Timeout sets the maximum run time of the job, e.g. 1h30m
+func (b *JobBuilder) Timeout(timeout string) *JobBuilder {
+	b.def.Timeout = timeout
+	return b
+}
+
+// Retry sets the number of times a failed job is retried.  delay may be empty.
+func (b *JobBuilder) Retry(count int, delay string) *JobBuilder {
+	b.def.Retry = &JobRetry{Retry: fmt.Sprint(count), Delay: delay}
+	return b
+}
+
+// LogLimit sets the log output limit, e.g. 100MB or 1000, and what to do when it is reached
+func (b *JobBuilder) LogLimit(limit, action, status string) *JobBuilder {
+	b.def.LogLimit = limit
+	b.def.LogLimitAction = action
+	b.def.LogLimitStatus = status
+	return b
+}
+
+// TimeZone sets the time zone the schedule is evaluated in
+func (b *JobBuilder) TimeZone(timeZone string) *JobBuilder {
+	b.def.TimeZone = timeZone
+	return b
+}
+
+// Option adds an input option
+func (b *JobBuilder) Option(option *JobOption) *JobBuilder {
+	b.def.Options = append(b.def.Options, option)
+	return b
+}
+
+// Step appends a workflow step, see Command, Script, ScriptURL, JobRef, StepPlugin and NodeStepPlugin
+func (b *JobBuilder) Step(step *JobStep) *JobBuilder {
+	b.def.Sequence.Commands = append(b.def.Sequence.Commands, step)
+	return b
+}
+
+// KeepGoing continues running the remaining steps when a step fails
+func (b *JobBuilder) KeepGoing(keepGoing bool) *JobBuilder {
+	b.def.Sequence.KeepGoing = keepGoing
+	return b
+}
+
+// Strategy sets the workflow strategy
+func (b *JobBuilder) Strategy(strategy JobSequenceStrategy) *JobBuilder {
+	b.def.Sequence.Strategy = strategy
+	return b
+}
+
+// Schedule sets a Quartz cron expression, e.g. 0 30 2 ? * MON-FRI *
+func (b *JobBuilder) Schedule(crontab string) *JobBuilder {
+	b.def.Schedule = &JobSchedule{Crontab: crontab}
+	return b
+}
+
+// NodeFilter dispatches the job to the nodes matching filter
+func (b *JobBuilder) NodeFilter(filter string) *JobBuilder {
+	if b.def.NodeFilter == nil {
+		b.def.NodeFilter = &JobNodeFilter{}
+	}
+	b.def.NodeFilter.Filter = filter
+	return b
+}
+
+// Dispatch controls how the job is dispatched to the nodes matching the node filter
+func (b *JobBuilder) Dispatch(dispatch *JobDispatch) *JobBuilder {
+	if b.def.NodeFilter == nil {
+		b.def.NodeFilter = &JobNodeFilter{}
+	}
+	b.def.NodeFilter.Dispatch = dispatch
+	return b
+}
+
+// Orchestrator sets the orchestrator plugin used to dispatch to nodes
+func (b *JobBuilder) Orchestrator(pluginType string, configuration map[string]string) *JobBuilder {
+	b.def.Orchestrator = &JobPlugin{Type: pluginType, Configuration: configuration}
+	return b
+}
+
+// OnSuccess sets the notifications sent when the job succeeds
+func (b *JobBuilder) OnSuccess(notification *JobNotification) *JobBuilder {
+	b.notifications().OnSuccess = notification
+	return b
+}
+
+// OnFailure sets the notifications sent when the job fails
+func (b *JobBuilder) OnFailure(notification *JobNotification) *JobBuilder {
+	b.notifications().OnFailure = notification
+	return b
+}
+
+// OnStart sets the notifications sent when the job starts
+func (b *JobBuilder) OnStart(notification *JobNotification) *JobBuilder {
+	b.notifications().OnStart = notification
+	return b
+}
+
+func (b *JobBuilder) notifications() *JobNotifications {
+	if b.def.Notification == nil {
+		b.def.Notification = &JobNotifications{}
+	}
+	return b.def.Notification
+}
+
+// Build validates and returns the job definition
+func (b *JobBuilder) Build() (*JobDefinition, error) {
+	if err := ValidateJobDefinition(b.def); err != nil {
+		return nil, err
+	}
+	return b.def, nil
+}
+
+// ImportJobsInput validates the job definition and returns the input needed to create it with Jobs.Import
+func (b *JobBuilder) ImportJobsInput() (*ImportJobsInput, error) {
+	return NewImportJobsInput(b.def)
+}
+
+// Command returns a step that runs a shell command
+func Command(command string) *JobStep {
+	return &JobStep{Exec: command}
+}
+
+// Script returns a step that runs an inline script
+func Script(script, args string) *JobStep {
+	return &JobStep{Script: script, Args: args}
+}
+
+// ScriptURL returns a step that downloads and runs a script
+func ScriptURL(url, args string) *JobStep {
+	return &JobStep{ScriptURL: url, Args: args}
+}
+
+// JobRef returns a step that runs another job, referenced by group and name
+func JobRef(group, name, args string) *JobStep {
+	return &JobStep{JobRef: &JobReference{Group: group, Name: name, Args: args}}
+}
+
+// StepPlugin returns a step that runs a workflow step plugin once for the whole job
+func StepPlugin(pluginType string, configuration map[string]string) *JobStep {
+	return &JobStep{Type: pluginType, Configuration: configuration}
+}
+
+// NodeStepPlugin returns a step that runs a node step plugin on each node
+func NodeStepPlugin(pluginType string, configuration map[string]string) *JobStep {
+	return &JobStep{NodeStep: true, Type: pluginType, Configuration: configuration}
+}
+
+// NewImportJobsInput validates the job definitions and serializes them for Jobs.Import
+func NewImportJobsInput(defs ...*JobDefinition) (*ImportJobsInput, error) {
+	for _, def := range defs {
+		if err := ValidateJobDefinition(def); err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := MarshalJobDefinitions(JobFormatYAML, defs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportJobsInput{
+		FileFormat: JobFormatYAML,
+		RawContent: content,
+	}, nil
+}
+
+// ValidateJobDefinition checks the definition for the mistakes Rundeck would otherwise reject on import.
+// The returned error is a *JobValidationError.
+func ValidateJobDefinition(def *JobDefinition) error {
+	if def == nil {
+		return &JobValidationError{Problems: []string{"definition is nil"}}
+	}
+
+	var problems []string
+	if strings.TrimSpace(def.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if strings.HasPrefix(def.Group, "/") || strings.HasSuffix(def.Group, "/") {
+		problems = append(problems, "group must not start or end with /")
+	}
+
+	seen := make(map[string]bool)
+	for i, option := range def.Options {
+		if option == nil || option.Name == "" {
+			problems = append(problems, fmt.Sprintf("option %d: name is required", i+1))
+			continue
+		}
+		if seen[option.Name] {
+			problems = append(problems, fmt.Sprintf("option %s: duplicate option name", option.Name))
+		}
+		seen[option.Name] = true
+
+		if option.Enforced && len(option.Values) == 0 && option.ValuesURL == "" {
+			problems = append(problems, fmt.Sprintf("option %s: enforced options need values", option.Name))
+		}
+		if option.Enforced && option.Default != "" && len(option.Values) > 0 && !contains(option.Values, option.Default) {
+			problems = append(problems, fmt.Sprintf("option %s: default %q is not one of the allowed values", option.Name, option.Default))
+		}
+		if option.Secure && option.Type == JobOptionTypeFile {
+			problems = append(problems, fmt.Sprintf("option %s: file options cannot be secure", option.Name))
+		}
+	}
+
+	if len(def.Sequence.Commands) == 0 {
+		problems = append(problems, "at least one step is required")
+	}
+	for i, step := range def.Sequence.Commands {
+		problems = append(problems, validateJobStep(fmt.Sprintf("step %d", i+1), step)...)
+	}
+
+	switch def.Sequence.Strategy {
+	case "", JobSequenceStrategyNodeFirst, JobSequenceStrategySequential, JobSequenceStrategyParallel:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown workflow strategy %q", def.Sequence.Strategy))
+	}
+
+	if def.Schedule != nil {
+		if _, err := ParseJobSchedule(def.Schedule); err != nil {
+			problems = append(problems, "schedule: "+err.Error())
+		}
+	}
+
+	switch def.LogLimitAction {
+	case "", "halt", "truncate":
+	default:
+		problems = append(problems, fmt.Sprintf("unknown log limit action %q", def.LogLimitAction))
+	}
+
+	if len(problems) > 0 {
+		return &JobValidationError{Job: joinGroupName(def.Group, def.Name), Problems: problems}
+	}
+	return nil
+}
+
+func validateJobStep(label string, step *JobStep) []string {
+	if step == nil {
+		return []string{label + ": step is nil"}
+	}
+
+	var problems []string
+	switch step.Kind() {
+	case JobStepKindCommand:
+		if strings.TrimSpace(step.Exec) == "" {
+			problems = append(problems, label+": command is empty")
+		}
+	case JobStepKindJobRef:
+		if step.JobRef.Name == "" && step.JobRef.UUID == "" {
+			problems = append(problems, label+": job reference needs a name or uuid")
+		}
+	}
+
+	if step.ErrorHandler != nil {
+		problems = append(problems, validateJobStep(label+" error handler", step.ErrorHandler)...)
+	}
+	return problems
+}
+
+func joinGroupName(group, name string) string {
+	if group == "" {
+		return name
+	}
+	return group + "/" + name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
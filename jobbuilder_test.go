@@ -0,0 +1,81 @@
+package rundeck_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func TestJobBuilderImport(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "JobBuilder"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("JobBuilder")
+
+	input, err := rundeck.NewJob("deploy").
+		Group("ops/web").
+		Option(&rundeck.JobOption{Name: "env", Required: true, Values: []string{"dev", "prod"}, Enforced: true, Default: "dev"}).
+		Step(rundeck.Command("deploy.sh ${option.env}")).
+		Step(rundeck.NodeStepPlugin("copyfile", map[string]string{"source": "/tmp/a"})).
+		Schedule("0 30 2 ? * MON-FRI *").
+		NodeFilter("tags: web").
+		ImportJobsInput()
+	if err != nil {
+		t.Fatal("failed to build job", err)
+	}
+
+	imported, err := cli.Jobs().Import("JobBuilder", input)
+	if err != nil {
+		t.Fatal("failed to import job", err)
+	}
+	if len(imported.Succeeded) != 1 {
+		t.Fatalf("unexpected import response: %+v\n", imported)
+	}
+
+	def, err := cli.Jobs().GetJobDefinition(imported.Succeeded[0].ID)
+	if err != nil {
+		t.Fatal("failed to get job definition", err)
+	}
+	if def.Group != "ops/web" || def.Schedule.Crontab != "0 30 2 ? * MON-FRI *" || def.NodeFilter.Filter != "tags: web" || len(def.Sequence.Commands) != 2 {
+		t.Errorf("unexpected job definition: %+v\n", def)
+	}
+}
+
+func TestJobBuilderValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		builder *rundeck.JobBuilder
+		problem string
+	}{
+		{"missing name", rundeck.NewJob("").Step(rundeck.Command("uptime")), "name is required"},
+		{"no steps", rundeck.NewJob("empty"), "at least one step is required"},
+		{"empty command", rundeck.NewJob("blank").Step(rundeck.Command(" ")), "step 1: command is empty"},
+		{
+			"duplicate option",
+			rundeck.NewJob("dupe").
+				Option(&rundeck.JobOption{Name: "env"}).
+				Option(&rundeck.JobOption{Name: "env"}).
+				Step(rundeck.Command("uptime")),
+			"option env: duplicate option name",
+		},
+		{"bad cron field count", rundeck.NewJob("cron").Step(rundeck.Command("uptime")).Schedule("0 0 * * *"), "must have 6 or 7 fields"},
+		{"bad cron day fields", rundeck.NewJob("cron").Step(rundeck.Command("uptime")).Schedule("0 0 0 * * * *"), "exactly one of day of month and day of week"},
+	}
+
+	for _, c := range cases {
+		_, err := c.builder.Build()
+
+		var validationErr *rundeck.JobValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s: expected a JobValidationError, received %v\n", c.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.problem) {
+			t.Errorf("%s: expected %q in %q\n", c.name, c.problem, err)
+		}
+	}
+}
@@ -0,0 +1,577 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// JobStepKind identifies what a workflow step does
+type JobStepKind string
+
+const (
+	JobStepKindCommand    JobStepKind = "command"
+	JobStepKindScript     JobStepKind = "script"
+	JobStepKindScriptURL  JobStepKind = "scripturl"
+	JobStepKindScriptFile JobStepKind = "scriptfile"
+	JobStepKindJobRef     JobStepKind = "jobref"
+	JobStepKindPlugin     JobStepKind = "plugin"
+)
+
+// JobOptionType is the type of a job option
+type JobOptionType string
+
+const (
+	JobOptionTypeText JobOptionType = ""
+	JobOptionTypeFile JobOptionType = "file"
+)
+
+// JobSequenceStrategy is the workflow strategy used to run the steps
+type JobSequenceStrategy string
+
+const (
+	JobSequenceStrategyNodeFirst  JobSequenceStrategy = "node-first"
+	JobSequenceStrategySequential JobSequenceStrategy = "sequential"
+	JobSequenceStrategyParallel   JobSequenceStrategy = "parallel"
+)
+
+// JobDefinition is the full definition of a job, as moved by the import and export endpoints.
+//
+// Use ParseJobDefinitions and MarshalJobDefinitions to convert to and from the Rundeck YAML and XML formats.
+type JobDefinition struct {
+	ID                         string            `yaml:"id,omitempty"`
+	UUID                       string            `yaml:"uuid,omitempty"`
+	Name                       string            `yaml:"name"`
+	Group                      string            `yaml:"group,omitempty"`
+	Description                string            `yaml:"description"`
+	ExecutionEnabled           *bool             `yaml:"executionEnabled,omitempty"`
+	ScheduleEnabled            *bool             `yaml:"scheduleEnabled,omitempty"`
+	LogLevel                   LogLevel          `yaml:"loglevel,omitempty"`
+	MultipleExecutions         bool              `yaml:"multipleExecutions,omitempty"`
+	Timeout                    string            `yaml:"timeout,omitempty"`
+	Retry                      *JobRetry         `yaml:"retry,omitempty"`
+	LogLimit                   string            `yaml:"loglimit,omitempty"`
+	LogLimitAction             string            `yaml:"loglimitAction,omitempty"`
+	LogLimitStatus             string            `yaml:"loglimitStatus,omitempty"`
+	TimeZone                   string            `yaml:"timeZone,omitempty"`
+	Options                    JobOptions        `yaml:"options,omitempty"`
+	NodeFilter                 *JobNodeFilter    `yaml:"nodefilters,omitempty"`
+	NodesSelectedByDefault     *bool             `yaml:"nodesSelectedByDefault,omitempty"`
+	Schedule                   *JobSchedule      `yaml:"schedule,omitempty"`
+	Sequence                   JobSequence       `yaml:"sequence"`
+	Notification               *JobNotifications `yaml:"notification,omitempty"`
+	NotifyAvgDurationThreshold string            `yaml:"notifyAvgDurationThreshold,omitempty"`
+	Orchestrator               *JobPlugin        `yaml:"orchestrator,omitempty"`
+}
+
+// JobRetry is the number of times a failed job is retried, and the delay between attempts
+type JobRetry struct {
+	Retry string
+	Delay string
+}
+
+// JobOption is an input option of a job
+type JobOption struct {
+	Name         string        `yaml:"name"`
+	Label        string        `yaml:"label,omitempty"`
+	Description  string        `yaml:"description,omitempty"`
+	Type         JobOptionType `yaml:"type,omitempty"`
+	Required     bool          `yaml:"required,omitempty"`
+	Default      string        `yaml:"value,omitempty"`
+	Values       []string      `yaml:"values,omitempty"`
+	ValuesURL    string        `yaml:"valuesUrl,omitempty"`
+	Enforced     bool          `yaml:"enforced,omitempty"`
+	Regex        string        `yaml:"regex,omitempty"`
+	MultiValued  bool          `yaml:"multivalued,omitempty"`
+	Delimiter    string        `yaml:"delimiter,omitempty"`
+	Secure       bool          `yaml:"secure,omitempty"`
+	ValueExposed bool          `yaml:"valueExposed,omitempty"`
+	StoragePath  string        `yaml:"storagePath,omitempty"`
+	IsDate       bool          `yaml:"isDate,omitempty"`
+	DateFormat   string        `yaml:"dateFormat,omitempty"`
+	Hidden       bool          `yaml:"hidden,omitempty"`
+	SortValues   bool          `yaml:"sortValues,omitempty"`
+}
+
+// JobOptions is the ordered list of job options.  Both the list form and the older map form keyed
+// by option name are understood when parsing YAML.
+type JobOptions []*JobOption
+
+// JobNodeFilter selects the nodes a job, or a job reference, dispatches to
+type JobNodeFilter struct {
+	Filter   string       `yaml:"filter,omitempty"`
+	Dispatch *JobDispatch `yaml:"dispatch,omitempty"`
+}
+
+// JobDispatch controls how a job is dispatched to the selected nodes.  ThreadCount is kept as written since
+// it may be an option reference such as ${option.threads}; Threads reads it as a number.  A nil
+// ExcludePrecedence means Rundeck's default of true.
+type JobDispatch struct {
+	ThreadCount              string `yaml:"threadcount,omitempty"`
+	KeepGoing                bool   `yaml:"keepgoing"`
+	ExcludePrecedence        *bool  `yaml:"excludePrecedence,omitempty"`
+	RankAttribute            string `yaml:"rankAttribute,omitempty"`
+	RankOrder                string `yaml:"rankOrder,omitempty"`
+	SuccessOnEmptyNodeFilter bool   `yaml:"successOnEmptyNodeFilter,omitempty"`
+}
+
+// JobSchedule is when a job runs.  Either Crontab is set, or the individual fields are, mirroring
+// the structured form used in job definitions.  Every field uses Quartz cron syntax.
+type JobSchedule struct {
+	Crontab    string
+	Seconds    string
+	Minute     string
+	Hour       string
+	DayOfMonth string
+	Month      string
+	DayOfWeek  string
+	Year       string
+}
+
+// JobSequence is the workflow of a job
+type JobSequence struct {
+	KeepGoing bool                `yaml:"keepgoing"`
+	Strategy  JobSequenceStrategy `yaml:"strategy,omitempty"`
+	Commands  []*JobStep          `yaml:"commands"`
+}
+
+// JobStep is a single workflow step.  The populated fields determine its kind, see Kind.
+type JobStep struct {
+	Description string `yaml:"description,omitempty"`
+
+	// Exec is set for command steps
+	Exec string `yaml:"exec,omitempty"`
+
+	// Script, ScriptURL or ScriptFile are set for script steps, along with the fields that follow
+	Script                string `yaml:"script,omitempty"`
+	ScriptURL             string `yaml:"scripturl,omitempty"`
+	ScriptFile            string `yaml:"scriptfile,omitempty"`
+	Args                  string `yaml:"args,omitempty"`
+	ScriptInterpreter     string `yaml:"scriptInterpreter,omitempty"`
+	InterpreterArgsQuoted bool   `yaml:"interpreterArgsQuoted,omitempty"`
+	FileExtension         string `yaml:"fileExtension,omitempty"`
+
+	// JobRef is set for job reference steps
+	JobRef *JobReference `yaml:"jobref,omitempty"`
+
+	// Type and Configuration are set for plugin steps.  NodeStep distinguishes node step plugins
+	// from workflow step plugins.
+	NodeStep      bool              `yaml:"nodeStep,omitempty"`
+	Type          string            `yaml:"type,omitempty"`
+	Configuration map[string]string `yaml:"configuration,omitempty"`
+
+	// ErrorHandler runs when the step fails
+	ErrorHandler       *JobStep `yaml:"errorhandler,omitempty"`
+	KeepGoingOnSuccess bool     `yaml:"keepgoingOnSuccess,omitempty"`
+}
+
+// JobReference is a step that runs another job
+type JobReference struct {
+	Name                string         `yaml:"name,omitempty"`
+	Group               string         `yaml:"group,omitempty"`
+	UUID                string         `yaml:"uuid,omitempty"`
+	Project             string         `yaml:"project,omitempty"`
+	Args                string         `yaml:"args,omitempty"`
+	NodeStep            bool           `yaml:"nodeStep,omitempty"`
+	ImportOptions       bool           `yaml:"importOptions,omitempty"`
+	FailOnDisable       bool           `yaml:"failOnDisable,omitempty"`
+	IgnoreNotifications bool           `yaml:"ignoreNotifications,omitempty"`
+	ChildNodes          bool           `yaml:"childNodes,omitempty"`
+	NodeFilter          *JobNodeFilter `yaml:"nodefilters,omitempty"`
+}
+
+// JobNotifications are the notifications sent for each job event
+type JobNotifications struct {
+	OnSuccess          *JobNotification `yaml:"onsuccess,omitempty"`
+	OnFailure          *JobNotification `yaml:"onfailure,omitempty"`
+	OnStart            *JobNotification `yaml:"onstart,omitempty"`
+	OnAvgDuration      *JobNotification `yaml:"onavgduration,omitempty"`
+	OnRetryableFailure *JobNotification `yaml:"onretryablefailure,omitempty"`
+}
+
+// JobNotification is the set of notifications sent for a single job event
+type JobNotification struct {
+	Email   *JobEmailNotification `yaml:"email,omitempty"`
+	URLs    string                `yaml:"urls,omitempty"`
+	Plugins JobPlugins            `yaml:"plugin,omitempty"`
+}
+
+// JobEmailNotification sends an email
+type JobEmailNotification struct {
+	Recipients string `yaml:"recipients"`
+	Subject    string `yaml:"subject,omitempty"`
+	AttachLog  bool   `yaml:"attachLog,omitempty"`
+}
+
+// JobPlugin is a plugin type along with its configuration, used for notifications and orchestrators
+type JobPlugin struct {
+	Type          string            `yaml:"type"`
+	Configuration map[string]string `yaml:"configuration,omitempty"`
+}
+
+// JobPlugins is a list of plugins.  A single plugin is also understood when parsing YAML.
+type JobPlugins []*JobPlugin
+
+// Kind returns the kind of step based on the populated fields
+func (s *JobStep) Kind() JobStepKind {
+	switch {
+	case s.JobRef != nil:
+		return JobStepKindJobRef
+	case s.Script != "":
+		return JobStepKindScript
+	case s.ScriptURL != "":
+		return JobStepKindScriptURL
+	case s.ScriptFile != "":
+		return JobStepKindScriptFile
+	case s.Type != "":
+		return JobStepKindPlugin
+	}
+	return JobStepKindCommand
+}
+
+// CronExpression returns the schedule as a seven field Quartz cron expression
+func (s *JobSchedule) CronExpression() string {
+	if s.Crontab != "" {
+		return s.Crontab
+	}
+
+	dayOfMonth, dayOfWeek := s.DayOfMonth, s.DayOfWeek
+	if dayOfMonth == "" && dayOfWeek == "" {
+		dayOfWeek = "*"
+	}
+	// Quartz requires exactly one of the day fields to be '?'
+	if dayOfMonth == "" || (dayOfWeek != "" && dayOfWeek != "?") {
+		dayOfMonth = "?"
+	}
+	if dayOfWeek == "" {
+		dayOfWeek = "?"
+	}
+
+	return fmt.Sprintf("%s %s %s %s %s %s %s",
+		valueOrDefault(s.Seconds, "0"),
+		valueOrDefault(s.Minute, "0"),
+		valueOrDefault(s.Hour, "0"),
+		dayOfMonth,
+		valueOrDefault(s.Month, "*"),
+		dayOfWeek,
+		valueOrDefault(s.Year, "*"),
+	)
+}
+
+// ParseJobDefinitions parses job definitions in the Rundeck YAML or XML format
+func ParseJobDefinitions(format JobFormat, content []byte) ([]*JobDefinition, error) {
+	switch format {
+	case JobFormatYAML:
+		var defs []*JobDefinition
+		if err := yaml.Unmarshal(content, &defs); err != nil {
+			return nil, err
+		}
+		return defs, nil
+	case JobFormatXML, "":
+		return parseXMLJobDefinitions(content)
+	}
+	return nil, fmt.Errorf("unsupported job format %q", format)
+}
+
+// MarshalJobDefinitions serializes job definitions into the Rundeck YAML or XML format
+func MarshalJobDefinitions(format JobFormat, defs []*JobDefinition) ([]byte, error) {
+	switch format {
+	case JobFormatYAML:
+		if defs == nil {
+			defs = []*JobDefinition{}
+		}
+		return yaml.Marshal(defs)
+	case JobFormatXML, "":
+		return marshalXMLJobDefinitions(defs)
+	}
+	return nil, fmt.Errorf("unsupported job format %q", format)
+}
+
+// GetJobDefinition returns the parsed definition of a job
+func (j *Jobs) GetJobDefinition(id string) (*JobDefinition, error) {
+	return j.GetJobDefinitionWithContext(context.Background(), id)
+}
+
+// GetJobDefinitionWithContext is the same as GetJobDefinition with the addition of the ability to pass a context.
+func (j *Jobs) GetJobDefinitionWithContext(ctx context.Context, id string) (*JobDefinition, error) {
+	format := JobFormatYAML
+	content, err := j.GetDefinitionWithContext(ctx, id, &format)
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := ParseJobDefinitions(format, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(defs) != 1 {
+		return nil, fmt.Errorf("expected 1 job definition for %s, received %d", id, len(defs))
+	}
+	return defs[0], nil
+}
+
+// ExportJobDefinitions returns the parsed definitions of a project's jobs.  input.Format is ignored.
+func (j *Jobs) ExportJobDefinitions(project string, input *ExportJobsInput) ([]*JobDefinition, error) {
+	return j.ExportJobDefinitionsWithContext(context.Background(), project, input)
+}
+
+// ExportJobDefinitionsWithContext is the same as ExportJobDefinitions with the addition of the ability to pass a context.
+func (j *Jobs) ExportJobDefinitionsWithContext(ctx context.Context, project string, input *ExportJobsInput) ([]*JobDefinition, error) {
+	var exportInput ExportJobsInput
+	if input != nil {
+		exportInput = *input
+	}
+	exportInput.Format = JobFormatYAML
+
+	content, err := j.ExportWithContext(ctx, project, &exportInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJobDefinitions(JobFormatYAML, content)
+}
+
+// UnmarshalYAML accepts the retry count on its own, or along with a delay
+func (r *JobRetry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var retry string
+	if err := unmarshal(&retry); err == nil {
+		r.Retry = retry
+		return nil
+	}
+
+	var full struct {
+		Retry string `yaml:"retry"`
+		Delay string `yaml:"delay"`
+	}
+	if err := unmarshal(&full); err != nil {
+		return err
+	}
+	r.Retry = full.Retry
+	r.Delay = full.Delay
+	return nil
+}
+
+// MarshalYAML writes the retry count on its own unless there is a delay
+func (r JobRetry) MarshalYAML() (interface{}, error) {
+	if r.Delay == "" {
+		return r.Retry, nil
+	}
+	return yaml.MapSlice{
+		{Key: "retry", Value: r.Retry},
+		{Key: "delay", Value: r.Delay},
+	}, nil
+}
+
+// UnmarshalYAML accepts a list of options or a map keyed by option name
+func (o *JobOptions) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []*JobOption
+	if err := unmarshal(&list); err == nil {
+		*o = list
+		return nil
+	}
+
+	var byName yaml.MapSlice
+	if err := unmarshal(&byName); err != nil {
+		return err
+	}
+
+	options := make(JobOptions, 0, len(byName))
+	for _, item := range byName {
+		bs, err := yaml.Marshal(item.Value)
+		if err != nil {
+			return err
+		}
+		var option JobOption
+		if err := yaml.Unmarshal(bs, &option); err != nil {
+			return err
+		}
+		option.Name = fmt.Sprint(item.Key)
+		options = append(options, &option)
+	}
+	*o = options
+	return nil
+}
+
+// UnmarshalYAML accepts a list of plugins or a single plugin
+func (p *JobPlugins) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []*JobPlugin
+	if err := unmarshal(&list); err == nil {
+		*p = list
+		return nil
+	}
+
+	var single JobPlugin
+	if err := unmarshal(&single); err != nil {
+		return err
+	}
+	*p = JobPlugins{&single}
+	return nil
+}
+
+// MarshalYAML writes a single plugin on its own, matching what Rundeck exports
+func (p JobPlugins) MarshalYAML() (interface{}, error) {
+	if len(p) == 1 {
+		return p[0], nil
+	}
+	return []*JobPlugin(p), nil
+}
+
+type yamlJobScheduleDay struct {
+	Day string `yaml:"day"`
+}
+
+type yamlJobSchedule struct {
+	Crontab string `yaml:"crontab,omitempty"`
+	Time    *struct {
+		Hour    string `yaml:"hour"`
+		Minute  string `yaml:"minute"`
+		Seconds string `yaml:"seconds"`
+	} `yaml:"time,omitempty"`
+	Month      string              `yaml:"month,omitempty"`
+	DayOfMonth *yamlJobScheduleDay `yaml:"dayofmonth,omitempty"`
+	Weekday    *yamlJobScheduleDay `yaml:"weekday,omitempty"`
+	Year       string              `yaml:"year,omitempty"`
+}
+
+// UnmarshalYAML reads either the crontab or the structured schedule form
+func (s *JobSchedule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw yamlJobSchedule
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	*s = JobSchedule{
+		Crontab: raw.Crontab,
+		Month:   raw.Month,
+		Year:    raw.Year,
+	}
+	if raw.Time != nil {
+		s.Hour = raw.Time.Hour
+		s.Minute = raw.Time.Minute
+		s.Seconds = raw.Time.Seconds
+	}
+	if raw.DayOfMonth != nil {
+		s.DayOfMonth = raw.DayOfMonth.Day
+	}
+	if raw.Weekday != nil {
+		s.DayOfWeek = raw.Weekday.Day
+	}
+	return nil
+}
+
+// MarshalYAML writes either the crontab or the structured schedule form
+func (s JobSchedule) MarshalYAML() (interface{}, error) {
+	if s.Crontab != "" {
+		return yamlJobSchedule{Crontab: s.Crontab}, nil
+	}
+
+	raw := yamlJobSchedule{
+		Month: valueOrDefault(s.Month, "*"),
+		Year:  valueOrDefault(s.Year, "*"),
+	}
+	raw.Time = &struct {
+		Hour    string `yaml:"hour"`
+		Minute  string `yaml:"minute"`
+		Seconds string `yaml:"seconds"`
+	}{
+		Hour:    valueOrDefault(s.Hour, "0"),
+		Minute:  valueOrDefault(s.Minute, "0"),
+		Seconds: valueOrDefault(s.Seconds, "0"),
+	}
+	if s.DayOfMonth != "" && s.DayOfMonth != "?" {
+		raw.DayOfMonth = &yamlJobScheduleDay{Day: s.DayOfMonth}
+	} else {
+		raw.Weekday = &yamlJobScheduleDay{Day: valueOrDefault(s.DayOfWeek, "*")}
+	}
+	return raw, nil
+}
+
+// UnmarshalYAML accepts nodeStep as either a boolean or the quoted string Rundeck sometimes exports
+func (r *JobReference) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw yaml.MapSlice
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for i, item := range raw {
+		if s, ok := item.Value.(string); ok && item.Key == "nodeStep" {
+			raw[i].Value = s == "true"
+		}
+	}
+
+	bs, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	type plain JobReference
+	return yaml.Unmarshal(bs, (*plain)(r))
+}
+
+// Threads returns the thread count as a number, false when it is unset or not a number
+func (d *JobDispatch) Threads() (int, bool) {
+	n, err := strconv.Atoi(d.ThreadCount)
+	return n, err == nil
+}
+
+// cloneJobDefinition returns a deep copy of def
+func cloneJobDefinition(def *JobDefinition) (*JobDefinition, error) {
+	bs, err := yaml.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	var clone JobDefinition
+	return &clone, yaml.Unmarshal(bs, &clone)
+}
+
+// normalizeJobDefinition returns a copy of def without identifiers and with Rundeck's defaults filled in,
+// so that two definitions of the same job compare equal however they were written
+func normalizeJobDefinition(def *JobDefinition) (*JobDefinition, error) {
+	n, err := cloneJobDefinition(def)
+	if err != nil {
+		return nil, err
+	}
+
+	n.ID, n.UUID = "", ""
+	n.ExecutionEnabled = boolOrDefault(n.ExecutionEnabled, true)
+	n.ScheduleEnabled = boolOrDefault(n.ScheduleEnabled, true)
+	n.NodesSelectedByDefault = boolOrDefault(n.NodesSelectedByDefault, true)
+	if n.LogLevel == "" {
+		n.LogLevel = JobLogLevelInfo
+	}
+	if n.Sequence.Strategy == "" {
+		n.Sequence.Strategy = JobSequenceStrategyNodeFirst
+	}
+	if n.Schedule != nil {
+		n.Schedule = &JobSchedule{Crontab: n.Schedule.CronExpression()}
+	}
+	if n.NodeFilter != nil && n.NodeFilter.Filter != "" {
+		// Rundeck exports a dispatch block for every job with a node filter, each field defaulted on its own
+		if n.NodeFilter.Dispatch == nil {
+			n.NodeFilter.Dispatch = &JobDispatch{}
+		}
+		dispatch := n.NodeFilter.Dispatch
+		dispatch.ThreadCount = valueOrDefault(dispatch.ThreadCount, "1")
+		dispatch.ExcludePrecedence = boolOrDefault(dispatch.ExcludePrecedence, true)
+		dispatch.RankOrder = valueOrDefault(dispatch.RankOrder, "ascending")
+	}
+	return n, nil
+}
+
+func boolOrDefault(v *bool, defaultValue bool) *bool {
+	if v == nil {
+		return &defaultValue
+	}
+	return v
+}
+
+func valueOrDefault(v, defaultValue string) string {
+	if v == "" {
+		return defaultValue
+	}
+	return v
+}
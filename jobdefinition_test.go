@@ -0,0 +1,250 @@
+package rundeck_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const fullJobYAML = `- id: 0d2c6a1e-8f0a-4b8e-9d1c-5b3a2e7f9c11
+  uuid: 0d2c6a1e-8f0a-4b8e-9d1c-5b3a2e7f9c11
+  name: deploy
+  group: ops/web
+  description: deploys the web tier
+  executionEnabled: true
+  scheduleEnabled: false
+  loglevel: INFO
+  multipleExecutions: true
+  timeout: 1h
+  retry:
+    retry: "3"
+    delay: 10s
+  loglimit: 100MB
+  loglimitAction: truncate
+  timeZone: America/Chicago
+  options:
+  - name: env
+    description: target environment
+    required: true
+    value: dev
+    values: [dev, prod]
+    enforced: true
+  - name: password
+    secure: true
+    storagePath: keys/deploy/password
+  nodefilters:
+    filter: 'tags: web'
+    dispatch:
+      threadcount: '2'
+      keepgoing: true
+      excludePrecedence: true
+      rankOrder: ascending
+  schedule:
+    month: '*'
+    time:
+      hour: '2'
+      minute: '30'
+      seconds: '0'
+    weekday:
+      day: MON-FRI
+    year: '*'
+  sequence:
+    keepgoing: false
+    strategy: node-first
+    commands:
+    - exec: echo hello
+      description: greet
+      errorhandler:
+        exec: echo failed
+        keepgoingOnSuccess: true
+    - script: |-
+        #!/bin/bash
+        echo script
+      args: -v
+      scriptInterpreter: bash -c
+      interpreterArgsQuoted: true
+    - scripturl: https://example.com/run.sh
+    - jobref:
+        name: migrate
+        group: ops/db
+        args: -env ${option.env}
+        nodeStep: 'true'
+        nodefilters:
+          filter: 'name: db1'
+    - nodeStep: true
+      type: copyfile
+      configuration:
+        source: /tmp/a
+        destination: /tmp/b
+  notification:
+    onfailure:
+      email:
+        recipients: ops@example.com
+        subject: deploy failed
+        attachLog: true
+      urls: https://hooks.example.com/deploy
+      plugin:
+        type: slack
+        configuration:
+          channel: '#ops'
+  orchestrator:
+    type: subset
+    configuration:
+      count: "1"
+`
+
+func TestJobDefinitionRoundTrip(t *testing.T) {
+	defs, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, []byte(fullJobYAML))
+	if err != nil {
+		t.Fatal("failed to parse yaml", err)
+	}
+	if len(defs) != 1 {
+		t.Fatalf("expected 1 definition, received %d\n", len(defs))
+	}
+
+	def := defs[0]
+	kinds := []rundeck.JobStepKind{
+		rundeck.JobStepKindCommand,
+		rundeck.JobStepKindScript,
+		rundeck.JobStepKindScriptURL,
+		rundeck.JobStepKindJobRef,
+		rundeck.JobStepKindPlugin,
+	}
+	for i, step := range def.Sequence.Commands {
+		if step.Kind() != kinds[i] {
+			t.Errorf("step %d: expected %s, received %s\n", i, kinds[i], step.Kind())
+		}
+	}
+	if !def.Sequence.Commands[3].JobRef.NodeStep {
+		t.Error("expected the quoted nodeStep on the job reference to parse as true")
+	}
+	if threads, ok := def.NodeFilter.Dispatch.Threads(); !ok || threads != 2 {
+		t.Errorf("expected the quoted threadcount to parse as 2, received %q\n", def.NodeFilter.Dispatch.ThreadCount)
+	}
+	if cron := def.Schedule.CronExpression(); cron != "0 30 2 ? * MON-FRI *" {
+		t.Errorf("unexpected cron expression: %s\n", cron)
+	}
+
+	for _, format := range []rundeck.JobFormat{rundeck.JobFormatXML, rundeck.JobFormatYAML} {
+		content, err := rundeck.MarshalJobDefinitions(format, defs)
+		if err != nil {
+			t.Fatalf("failed to marshal %s: %v\n", format, err)
+		}
+
+		parsed, err := rundeck.ParseJobDefinitions(format, content)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v\n%s\n", format, err, content)
+		}
+		if !reflect.DeepEqual(parsed, defs) {
+			t.Errorf("%s round trip changed the definition:\n%s\n", format, content)
+		}
+	}
+}
+
+func TestJobDefinitionLegacyOptionMap(t *testing.T) {
+	defs, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, []byte(`- name: legacy
+  options:
+    env:
+      required: true
+    region:
+      value: us-east-1
+  sequence:
+    commands:
+    - exec: uptime
+`))
+	if err != nil {
+		t.Fatal("failed to parse yaml", err)
+	}
+
+	options := defs[0].Options
+	if len(options) != 2 || options[0].Name != "env" || !options[0].Required || options[1].Name != "region" || options[1].Default != "us-east-1" {
+		t.Errorf("unexpected options: %+v %+v\n", options[0], options[1])
+	}
+}
+
+func TestJobDefinitionThreadCount(t *testing.T) {
+	defs, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, []byte(`- name: threads
+  nodefilters:
+    filter: 'tags: web'
+    dispatch:
+      threadcount: '${option.threads}'
+  sequence:
+    commands:
+    - exec: uptime
+- name: numbered
+  nodefilters:
+    filter: 'tags: web'
+    dispatch:
+      threadcount: 3
+  sequence:
+    commands:
+    - exec: uptime
+`))
+	if err != nil {
+		t.Fatal("failed to parse yaml", err)
+	}
+
+	option := defs[0].NodeFilter.Dispatch
+	if option.ThreadCount != "${option.threads}" {
+		t.Errorf("expected the option reference to be kept, received %q\n", option.ThreadCount)
+	}
+	if _, ok := option.Threads(); ok {
+		t.Error("expected an option reference not to read as a number")
+	}
+	if threads, ok := defs[1].NodeFilter.Dispatch.Threads(); !ok || threads != 3 {
+		t.Errorf("expected an unquoted threadcount to read as 3, received %q\n", defs[1].NodeFilter.Dispatch.ThreadCount)
+	}
+
+	for _, format := range []rundeck.JobFormat{rundeck.JobFormatXML, rundeck.JobFormatYAML} {
+		content, err := rundeck.MarshalJobDefinitions(format, defs)
+		if err != nil {
+			t.Fatalf("failed to marshal %s: %v\n", format, err)
+		}
+		parsed, err := rundeck.ParseJobDefinitions(format, content)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v\n%s\n", format, err, content)
+		}
+		if !reflect.DeepEqual(parsed, defs) {
+			t.Errorf("%s round trip changed the thread count:\n%s\n", format, content)
+		}
+	}
+}
+
+func TestGetJobDefinition(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "JobDefinitions"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("JobDefinitions")
+
+	imported, err := cli.Jobs().Import("JobDefinitions", &rundeck.ImportJobsInput{
+		FileFormat: rundeck.JobFormatYAML,
+		UUIDOption: rundeck.UUIDOptionRemove,
+		RawContent: []byte(fullJobYAML),
+	})
+	if err != nil {
+		t.Fatal("failed to import job", err)
+	}
+	if len(imported.Succeeded) != 1 {
+		t.Fatalf("unexpected import response: %+v\n", imported)
+	}
+	id := imported.Succeeded[0].ID
+
+	def, err := cli.Jobs().GetJobDefinition(id)
+	if err != nil {
+		t.Fatal("failed to get job definition", err)
+	}
+	if def.UUID != id || def.Name != "deploy" || len(def.Sequence.Commands) != 5 {
+		t.Errorf("unexpected job definition: %+v\n", def)
+	}
+
+	defs, err := cli.Jobs().ExportJobDefinitions("JobDefinitions", &rundeck.ExportJobsInput{Format: rundeck.JobFormatXML})
+	if err != nil {
+		t.Fatal("failed to export job definitions", err)
+	}
+	if len(defs) != 1 || defs[0].Orchestrator == nil || defs[0].Orchestrator.Configuration["count"] != "1" {
+		t.Errorf("unexpected exported definitions: %+v\n", defs)
+	}
+}
@@ -0,0 +1,740 @@
+package rundeck
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// The XML job format is shaped differently enough from the YAML one that it gets its own
+// set of types, converted to and from JobDefinition.
+
+type xmlJobList struct {
+	XMLName xml.Name            `xml:"joblist"`
+	Jobs    []*xmlJobDefinition `xml:"job"`
+}
+
+type xmlJobDefinition struct {
+	ID                         string            `xml:"id,omitempty"`
+	UUID                       string            `xml:"uuid,omitempty"`
+	Name                       string            `xml:"name"`
+	Group                      string            `xml:"group,omitempty"`
+	Description                string            `xml:"description"`
+	ExecutionEnabled           *bool             `xml:"executionEnabled,omitempty"`
+	ScheduleEnabled            *bool             `xml:"scheduleEnabled,omitempty"`
+	LogLevel                   LogLevel          `xml:"loglevel,omitempty"`
+	MultipleExecutions         bool              `xml:"multipleExecutions,omitempty"`
+	Timeout                    string            `xml:"timeout,omitempty"`
+	Retry                      *xmlRetry         `xml:"retry,omitempty"`
+	LogLimit                   string            `xml:"loglimit,omitempty"`
+	LogLimitAction             string            `xml:"loglimitAction,omitempty"`
+	LogLimitStatus             string            `xml:"loglimitStatus,omitempty"`
+	TimeZone                   string            `xml:"timeZone,omitempty"`
+	Context                    *xmlJobContext    `xml:"context,omitempty"`
+	Dispatch                   *xmlDispatch      `xml:"dispatch,omitempty"`
+	NodeFilters                *xmlNodeFilters   `xml:"nodefilters,omitempty"`
+	NodesSelectedByDefault     *bool             `xml:"nodesSelectedByDefault,omitempty"`
+	Schedule                   *xmlSchedule      `xml:"schedule,omitempty"`
+	Sequence                   xmlSequence       `xml:"sequence"`
+	Notification               *xmlNotifications `xml:"notification,omitempty"`
+	NotifyAvgDurationThreshold string            `xml:"notifyAvgDurationThreshold,omitempty"`
+	Orchestrator               *xmlOrchestrator  `xml:"orchestrator,omitempty"`
+}
+
+type xmlRetry struct {
+	Delay string `xml:"delay,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type xmlJobContext struct {
+	Options *xmlOptions `xml:"options,omitempty"`
+}
+
+type xmlOptions struct {
+	PreserveOrder bool         `xml:"preserveOrder,attr,omitempty"`
+	Options       []*xmlOption `xml:"option"`
+}
+
+type xmlOption struct {
+	Name                string `xml:"name,attr"`
+	Label               string `xml:"label,attr,omitempty"`
+	Type                string `xml:"type,attr,omitempty"`
+	Required            bool   `xml:"required,attr,omitempty"`
+	Value               string `xml:"value,attr,omitempty"`
+	Values              string `xml:"values,attr,omitempty"`
+	ValuesListDelimiter string `xml:"valuesListDelimiter,attr,omitempty"`
+	ValuesURL           string `xml:"valuesUrl,attr,omitempty"`
+	EnforcedValues      bool   `xml:"enforcedvalues,attr,omitempty"`
+	Regex               string `xml:"regex,attr,omitempty"`
+	MultiValued         bool   `xml:"multivalued,attr,omitempty"`
+	Delimiter           string `xml:"delimiter,attr,omitempty"`
+	Secure              bool   `xml:"secure,attr,omitempty"`
+	ValueExposed        bool   `xml:"valueExposed,attr,omitempty"`
+	StoragePath         string `xml:"storagePath,attr,omitempty"`
+	IsDate              bool   `xml:"isDate,attr,omitempty"`
+	DateFormat          string `xml:"dateFormat,attr,omitempty"`
+	Hidden              bool   `xml:"hidden,attr,omitempty"`
+	SortValues          bool   `xml:"sortValues,attr,omitempty"`
+	Description         string `xml:"description,omitempty"`
+}
+
+type xmlDispatch struct {
+	ThreadCount              string `xml:"threadcount,omitempty"`
+	KeepGoing                bool   `xml:"keepgoing"`
+	ExcludePrecedence        *bool  `xml:"excludePrecedence,omitempty"`
+	RankAttribute            string `xml:"rankAttribute,omitempty"`
+	RankOrder                string `xml:"rankOrder,omitempty"`
+	SuccessOnEmptyNodeFilter bool   `xml:"successOnEmptyNodeFilter,omitempty"`
+}
+
+type xmlNodeFilters struct {
+	Filter   string       `xml:"filter,omitempty"`
+	Dispatch *xmlDispatch `xml:"dispatch,omitempty"`
+}
+
+type xmlSchedule struct {
+	Crontab string                `xml:"crontab,attr,omitempty"`
+	Month   *xmlScheduleMonth     `xml:"month,omitempty"`
+	Time    *xmlScheduleTime      `xml:"time,omitempty"`
+	Weekday *xmlScheduleDayOfWeek `xml:"weekday,omitempty"`
+	Year    *xmlScheduleYear      `xml:"year,omitempty"`
+}
+
+type xmlScheduleMonth struct {
+	Day   string `xml:"day,attr,omitempty"`
+	Month string `xml:"month,attr"`
+}
+
+type xmlScheduleTime struct {
+	Hour    string `xml:"hour,attr"`
+	Minute  string `xml:"minute,attr"`
+	Seconds string `xml:"seconds,attr"`
+}
+
+type xmlScheduleDayOfWeek struct {
+	Day string `xml:"day,attr"`
+}
+
+type xmlScheduleYear struct {
+	Year string `xml:"year,attr"`
+}
+
+type xmlSequence struct {
+	KeepGoing bool       `xml:"keepgoing,attr"`
+	Strategy  string     `xml:"strategy,attr,omitempty"`
+	Commands  []*xmlStep `xml:"command"`
+}
+
+type xmlStep struct {
+	KeepGoingOnSuccess bool            `xml:"keepgoingOnSuccess,attr,omitempty"`
+	Description        string          `xml:"description,omitempty"`
+	Exec               string          `xml:"exec,omitempty"`
+	Script             *xmlCData       `xml:"script,omitempty"`
+	ScriptURL          string          `xml:"scripturl,omitempty"`
+	ScriptFile         string          `xml:"scriptfile,omitempty"`
+	ScriptArgs         string          `xml:"scriptargs,omitempty"`
+	ScriptInterpreter  *xmlInterpreter `xml:"scriptinterpreter,omitempty"`
+	FileExtension      string          `xml:"fileExtension,omitempty"`
+	JobRef             *xmlJobRef      `xml:"jobref,omitempty"`
+	NodeStepPlugin     *xmlPlugin      `xml:"node-step-plugin,omitempty"`
+	StepPlugin         *xmlPlugin      `xml:"step-plugin,omitempty"`
+	ErrorHandler       *xmlStep        `xml:"errorhandler,omitempty"`
+}
+
+type xmlCData struct {
+	Value string `xml:",cdata"`
+}
+
+type xmlInterpreter struct {
+	ArgsQuoted bool   `xml:"argsquoted,attr,omitempty"`
+	Value      string `xml:",chardata"`
+}
+
+type xmlJobRef struct {
+	Name                string          `xml:"name,attr,omitempty"`
+	Group               string          `xml:"group,attr,omitempty"`
+	Project             string          `xml:"project,attr,omitempty"`
+	NodeStep            bool            `xml:"nodeStep,attr,omitempty"`
+	ImportOptions       bool            `xml:"importOptions,attr,omitempty"`
+	FailOnDisable       bool            `xml:"failOnDisable,attr,omitempty"`
+	IgnoreNotifications bool            `xml:"ignoreNotifications,attr,omitempty"`
+	ChildNodes          bool            `xml:"childNodes,attr,omitempty"`
+	UUID                string          `xml:"uuid,omitempty"`
+	Arg                 *xmlJobRefArg   `xml:"arg,omitempty"`
+	NodeFilters         *xmlNodeFilters `xml:"nodefilters,omitempty"`
+}
+
+type xmlJobRefArg struct {
+	Line string `xml:"line,attr"`
+}
+
+type xmlPlugin struct {
+	Type          string            `xml:"type,attr"`
+	Configuration *xmlConfiguration `xml:"configuration,omitempty"`
+}
+
+type xmlConfiguration struct {
+	Entries []xmlEntry `xml:"entry"`
+}
+
+type xmlEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type xmlNotifications struct {
+	OnSuccess          *xmlNotification `xml:"onsuccess,omitempty"`
+	OnFailure          *xmlNotification `xml:"onfailure,omitempty"`
+	OnStart            *xmlNotification `xml:"onstart,omitempty"`
+	OnAvgDuration      *xmlNotification `xml:"onavgduration,omitempty"`
+	OnRetryableFailure *xmlNotification `xml:"onretryablefailure,omitempty"`
+}
+
+type xmlNotification struct {
+	Email   *xmlEmail    `xml:"email,omitempty"`
+	Webhook *xmlWebhook  `xml:"webhook,omitempty"`
+	Plugins []*xmlPlugin `xml:"plugin"`
+}
+
+type xmlEmail struct {
+	Recipients string `xml:"recipients,attr"`
+	Subject    string `xml:"subject,attr,omitempty"`
+	AttachLog  bool   `xml:"attachLog,attr,omitempty"`
+}
+
+type xmlWebhook struct {
+	URLs string `xml:"urls,attr"`
+}
+
+type xmlOrchestrator struct {
+	Configuration xmlElementMap `xml:"configuration"`
+	Type          string        `xml:"type"`
+}
+
+// xmlElementMap is a map written as one child element per key, as the orchestrator configuration is
+type xmlElementMap map[string]string
+
+func (m xmlElementMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range sortedStringKeys(m) {
+		if err := e.EncodeElement(m[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (m *xmlElementMap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	values := xmlElementMap{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var v string
+			if err := d.DecodeElement(&v, &t); err != nil {
+				return err
+			}
+			values[t.Name.Local] = v
+		case xml.EndElement:
+			*m = values
+			return nil
+		}
+	}
+}
+
+func parseXMLJobDefinitions(content []byte) ([]*JobDefinition, error) {
+	var list xmlJobList
+	if err := xml.Unmarshal(content, &list); err != nil {
+		return nil, err
+	}
+
+	defs := make([]*JobDefinition, 0, len(list.Jobs))
+	for _, job := range list.Jobs {
+		defs = append(defs, job.toDefinition())
+	}
+	return defs, nil
+}
+
+func marshalXMLJobDefinitions(defs []*JobDefinition) ([]byte, error) {
+	list := xmlJobList{}
+	for _, def := range defs {
+		list.Jobs = append(list.Jobs, newXMLJobDefinition(def))
+	}
+
+	bs, err := xml.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(bs, '\n')...), nil
+}
+
+func newXMLJobDefinition(def *JobDefinition) *xmlJobDefinition {
+	x := &xmlJobDefinition{
+		ID:                         def.ID,
+		UUID:                       def.UUID,
+		Name:                       def.Name,
+		Group:                      def.Group,
+		Description:                def.Description,
+		ExecutionEnabled:           def.ExecutionEnabled,
+		ScheduleEnabled:            def.ScheduleEnabled,
+		LogLevel:                   def.LogLevel,
+		MultipleExecutions:         def.MultipleExecutions,
+		Timeout:                    def.Timeout,
+		LogLimit:                   def.LogLimit,
+		LogLimitAction:             def.LogLimitAction,
+		LogLimitStatus:             def.LogLimitStatus,
+		TimeZone:                   def.TimeZone,
+		NodesSelectedByDefault:     def.NodesSelectedByDefault,
+		NotifyAvgDurationThreshold: def.NotifyAvgDurationThreshold,
+		Sequence: xmlSequence{
+			KeepGoing: def.Sequence.KeepGoing,
+			Strategy:  string(def.Sequence.Strategy),
+		},
+	}
+
+	if def.Retry != nil {
+		x.Retry = &xmlRetry{Delay: def.Retry.Delay, Value: def.Retry.Retry}
+	}
+
+	if len(def.Options) > 0 {
+		options := &xmlOptions{PreserveOrder: true}
+		for _, o := range def.Options {
+			options.Options = append(options.Options, newXMLOption(o))
+		}
+		x.Context = &xmlJobContext{Options: options}
+	}
+
+	if def.NodeFilter != nil {
+		x.NodeFilters = &xmlNodeFilters{Filter: def.NodeFilter.Filter}
+		x.Dispatch = newXMLDispatch(def.NodeFilter.Dispatch)
+	}
+
+	if s := def.Schedule; s != nil {
+		if s.Crontab != "" {
+			x.Schedule = &xmlSchedule{Crontab: s.Crontab}
+		} else {
+			x.Schedule = &xmlSchedule{
+				Month: &xmlScheduleMonth{Month: valueOrDefault(s.Month, "*")},
+				Time: &xmlScheduleTime{
+					Hour:    valueOrDefault(s.Hour, "0"),
+					Minute:  valueOrDefault(s.Minute, "0"),
+					Seconds: valueOrDefault(s.Seconds, "0"),
+				},
+				Year: &xmlScheduleYear{Year: valueOrDefault(s.Year, "*")},
+			}
+			if s.DayOfMonth != "" && s.DayOfMonth != "?" {
+				x.Schedule.Month.Day = s.DayOfMonth
+			} else {
+				x.Schedule.Weekday = &xmlScheduleDayOfWeek{Day: valueOrDefault(s.DayOfWeek, "*")}
+			}
+		}
+	}
+
+	for _, step := range def.Sequence.Commands {
+		x.Sequence.Commands = append(x.Sequence.Commands, newXMLStep(step))
+	}
+
+	if n := def.Notification; n != nil {
+		x.Notification = &xmlNotifications{
+			OnSuccess:          newXMLNotification(n.OnSuccess),
+			OnFailure:          newXMLNotification(n.OnFailure),
+			OnStart:            newXMLNotification(n.OnStart),
+			OnAvgDuration:      newXMLNotification(n.OnAvgDuration),
+			OnRetryableFailure: newXMLNotification(n.OnRetryableFailure),
+		}
+	}
+
+	if def.Orchestrator != nil {
+		x.Orchestrator = &xmlOrchestrator{
+			Type:          def.Orchestrator.Type,
+			Configuration: xmlElementMap(def.Orchestrator.Configuration),
+		}
+	}
+
+	return x
+}
+
+func (x *xmlJobDefinition) toDefinition() *JobDefinition {
+	def := &JobDefinition{
+		ID:                         x.ID,
+		UUID:                       x.UUID,
+		Name:                       x.Name,
+		Group:                      x.Group,
+		Description:                x.Description,
+		ExecutionEnabled:           x.ExecutionEnabled,
+		ScheduleEnabled:            x.ScheduleEnabled,
+		LogLevel:                   x.LogLevel,
+		MultipleExecutions:         x.MultipleExecutions,
+		Timeout:                    x.Timeout,
+		LogLimit:                   x.LogLimit,
+		LogLimitAction:             x.LogLimitAction,
+		LogLimitStatus:             x.LogLimitStatus,
+		TimeZone:                   x.TimeZone,
+		NodesSelectedByDefault:     x.NodesSelectedByDefault,
+		NotifyAvgDurationThreshold: x.NotifyAvgDurationThreshold,
+		Sequence: JobSequence{
+			KeepGoing: x.Sequence.KeepGoing,
+			Strategy:  JobSequenceStrategy(x.Sequence.Strategy),
+		},
+	}
+
+	if x.Retry != nil {
+		def.Retry = &JobRetry{Retry: strings.TrimSpace(x.Retry.Value), Delay: x.Retry.Delay}
+	}
+
+	if x.Context != nil && x.Context.Options != nil {
+		for _, o := range x.Context.Options.Options {
+			def.Options = append(def.Options, o.toOption())
+		}
+	}
+
+	if x.NodeFilters != nil || x.Dispatch != nil {
+		def.NodeFilter = &JobNodeFilter{Dispatch: x.Dispatch.toDispatch()}
+		if x.NodeFilters != nil {
+			def.NodeFilter.Filter = x.NodeFilters.Filter
+		}
+	}
+
+	if s := x.Schedule; s != nil {
+		def.Schedule = &JobSchedule{Crontab: s.Crontab}
+		if s.Month != nil {
+			def.Schedule.Month = s.Month.Month
+			def.Schedule.DayOfMonth = s.Month.Day
+		}
+		if s.Time != nil {
+			def.Schedule.Hour = s.Time.Hour
+			def.Schedule.Minute = s.Time.Minute
+			def.Schedule.Seconds = s.Time.Seconds
+		}
+		if s.Weekday != nil {
+			def.Schedule.DayOfWeek = s.Weekday.Day
+		}
+		if s.Year != nil {
+			def.Schedule.Year = s.Year.Year
+		}
+	}
+
+	for _, step := range x.Sequence.Commands {
+		def.Sequence.Commands = append(def.Sequence.Commands, step.toStep())
+	}
+
+	if n := x.Notification; n != nil {
+		def.Notification = &JobNotifications{
+			OnSuccess:          n.OnSuccess.toNotification(),
+			OnFailure:          n.OnFailure.toNotification(),
+			OnStart:            n.OnStart.toNotification(),
+			OnAvgDuration:      n.OnAvgDuration.toNotification(),
+			OnRetryableFailure: n.OnRetryableFailure.toNotification(),
+		}
+	}
+
+	if x.Orchestrator != nil {
+		def.Orchestrator = &JobPlugin{
+			Type:          x.Orchestrator.Type,
+			Configuration: nilIfEmpty(x.Orchestrator.Configuration),
+		}
+	}
+
+	return def
+}
+
+func newXMLOption(o *JobOption) *xmlOption {
+	x := &xmlOption{
+		Name:           o.Name,
+		Label:          o.Label,
+		Type:           string(o.Type),
+		Required:       o.Required,
+		Value:          o.Default,
+		ValuesURL:      o.ValuesURL,
+		EnforcedValues: o.Enforced,
+		Regex:          o.Regex,
+		MultiValued:    o.MultiValued,
+		Delimiter:      o.Delimiter,
+		Secure:         o.Secure,
+		ValueExposed:   o.ValueExposed,
+		StoragePath:    o.StoragePath,
+		IsDate:         o.IsDate,
+		DateFormat:     o.DateFormat,
+		Hidden:         o.Hidden,
+		SortValues:     o.SortValues,
+		Description:    o.Description,
+	}
+
+	if len(o.Values) > 0 {
+		x.Values = strings.Join(o.Values, ",")
+		for _, v := range o.Values {
+			if strings.Contains(v, ",") {
+				// fall back to a delimiter that is far less likely to appear in a value
+				x.ValuesListDelimiter = "|"
+				x.Values = strings.Join(o.Values, "|")
+				break
+			}
+		}
+	}
+
+	return x
+}
+
+func (x *xmlOption) toOption() *JobOption {
+	o := &JobOption{
+		Name:         x.Name,
+		Label:        x.Label,
+		Description:  x.Description,
+		Type:         JobOptionType(x.Type),
+		Required:     x.Required,
+		Default:      x.Value,
+		ValuesURL:    x.ValuesURL,
+		Enforced:     x.EnforcedValues,
+		Regex:        x.Regex,
+		MultiValued:  x.MultiValued,
+		Delimiter:    x.Delimiter,
+		Secure:       x.Secure,
+		ValueExposed: x.ValueExposed,
+		StoragePath:  x.StoragePath,
+		IsDate:       x.IsDate,
+		DateFormat:   x.DateFormat,
+		Hidden:       x.Hidden,
+		SortValues:   x.SortValues,
+	}
+
+	if x.Values != "" {
+		o.Values = strings.Split(x.Values, valueOrDefault(x.ValuesListDelimiter, ","))
+	}
+
+	return o
+}
+
+func newXMLDispatch(d *JobDispatch) *xmlDispatch {
+	if d == nil {
+		return nil
+	}
+	return &xmlDispatch{
+		ThreadCount:              d.ThreadCount,
+		KeepGoing:                d.KeepGoing,
+		ExcludePrecedence:        d.ExcludePrecedence,
+		RankAttribute:            d.RankAttribute,
+		RankOrder:                d.RankOrder,
+		SuccessOnEmptyNodeFilter: d.SuccessOnEmptyNodeFilter,
+	}
+}
+
+func (x *xmlDispatch) toDispatch() *JobDispatch {
+	if x == nil {
+		return nil
+	}
+	return &JobDispatch{
+		ThreadCount:              x.ThreadCount,
+		KeepGoing:                x.KeepGoing,
+		ExcludePrecedence:        x.ExcludePrecedence,
+		RankAttribute:            x.RankAttribute,
+		RankOrder:                x.RankOrder,
+		SuccessOnEmptyNodeFilter: x.SuccessOnEmptyNodeFilter,
+	}
+}
+
+func newXMLStep(s *JobStep) *xmlStep {
+	if s == nil {
+		return nil
+	}
+
+	x := &xmlStep{
+		KeepGoingOnSuccess: s.KeepGoingOnSuccess,
+		Description:        s.Description,
+		ErrorHandler:       newXMLStep(s.ErrorHandler),
+	}
+
+	switch s.Kind() {
+	case JobStepKindCommand:
+		x.Exec = s.Exec
+	case JobStepKindScript, JobStepKindScriptURL, JobStepKindScriptFile:
+		if s.Script != "" {
+			x.Script = &xmlCData{Value: s.Script}
+		}
+		x.ScriptURL = s.ScriptURL
+		x.ScriptFile = s.ScriptFile
+		x.ScriptArgs = s.Args
+		x.FileExtension = s.FileExtension
+		if s.ScriptInterpreter != "" {
+			x.ScriptInterpreter = &xmlInterpreter{ArgsQuoted: s.InterpreterArgsQuoted, Value: s.ScriptInterpreter}
+		}
+	case JobStepKindJobRef:
+		ref := s.JobRef
+		x.JobRef = &xmlJobRef{
+			Name:                ref.Name,
+			Group:               ref.Group,
+			Project:             ref.Project,
+			NodeStep:            ref.NodeStep,
+			ImportOptions:       ref.ImportOptions,
+			FailOnDisable:       ref.FailOnDisable,
+			IgnoreNotifications: ref.IgnoreNotifications,
+			ChildNodes:          ref.ChildNodes,
+			UUID:                ref.UUID,
+		}
+		if ref.Args != "" {
+			x.JobRef.Arg = &xmlJobRefArg{Line: ref.Args}
+		}
+		if ref.NodeFilter != nil {
+			x.JobRef.NodeFilters = &xmlNodeFilters{
+				Filter:   ref.NodeFilter.Filter,
+				Dispatch: newXMLDispatch(ref.NodeFilter.Dispatch),
+			}
+		}
+	case JobStepKindPlugin:
+		plugin := &xmlPlugin{Type: s.Type, Configuration: newXMLConfiguration(s.Configuration)}
+		if s.NodeStep {
+			x.NodeStepPlugin = plugin
+		} else {
+			x.StepPlugin = plugin
+		}
+	}
+
+	return x
+}
+
+func (x *xmlStep) toStep() *JobStep {
+	if x == nil {
+		return nil
+	}
+
+	s := &JobStep{
+		Description:        x.Description,
+		Exec:               x.Exec,
+		ScriptURL:          x.ScriptURL,
+		ScriptFile:         x.ScriptFile,
+		Args:               x.ScriptArgs,
+		FileExtension:      x.FileExtension,
+		ErrorHandler:       x.ErrorHandler.toStep(),
+		KeepGoingOnSuccess: x.KeepGoingOnSuccess,
+	}
+
+	if x.Script != nil {
+		s.Script = x.Script.Value
+	}
+	if x.ScriptInterpreter != nil {
+		s.ScriptInterpreter = x.ScriptInterpreter.Value
+		s.InterpreterArgsQuoted = x.ScriptInterpreter.ArgsQuoted
+	}
+
+	if ref := x.JobRef; ref != nil {
+		s.JobRef = &JobReference{
+			Name:                ref.Name,
+			Group:               ref.Group,
+			UUID:                ref.UUID,
+			Project:             ref.Project,
+			NodeStep:            ref.NodeStep,
+			ImportOptions:       ref.ImportOptions,
+			FailOnDisable:       ref.FailOnDisable,
+			IgnoreNotifications: ref.IgnoreNotifications,
+			ChildNodes:          ref.ChildNodes,
+		}
+		if ref.Arg != nil {
+			s.JobRef.Args = ref.Arg.Line
+		}
+		if ref.NodeFilters != nil {
+			s.JobRef.NodeFilter = &JobNodeFilter{
+				Filter:   ref.NodeFilters.Filter,
+				Dispatch: ref.NodeFilters.Dispatch.toDispatch(),
+			}
+		}
+	}
+
+	if x.NodeStepPlugin != nil {
+		s.NodeStep = true
+		s.Type = x.NodeStepPlugin.Type
+		s.Configuration = x.NodeStepPlugin.Configuration.toMap()
+	} else if x.StepPlugin != nil {
+		s.Type = x.StepPlugin.Type
+		s.Configuration = x.StepPlugin.Configuration.toMap()
+	}
+
+	return s
+}
+
+func newXMLNotification(n *JobNotification) *xmlNotification {
+	if n == nil {
+		return nil
+	}
+
+	x := &xmlNotification{}
+	if n.Email != nil {
+		x.Email = &xmlEmail{
+			Recipients: n.Email.Recipients,
+			Subject:    n.Email.Subject,
+			AttachLog:  n.Email.AttachLog,
+		}
+	}
+	if n.URLs != "" {
+		x.Webhook = &xmlWebhook{URLs: n.URLs}
+	}
+	for _, p := range n.Plugins {
+		x.Plugins = append(x.Plugins, &xmlPlugin{Type: p.Type, Configuration: newXMLConfiguration(p.Configuration)})
+	}
+	return x
+}
+
+func (x *xmlNotification) toNotification() *JobNotification {
+	if x == nil {
+		return nil
+	}
+
+	n := &JobNotification{}
+	if x.Email != nil {
+		n.Email = &JobEmailNotification{
+			Recipients: x.Email.Recipients,
+			Subject:    x.Email.Subject,
+			AttachLog:  x.Email.AttachLog,
+		}
+	}
+	if x.Webhook != nil {
+		n.URLs = x.Webhook.URLs
+	}
+	for _, p := range x.Plugins {
+		n.Plugins = append(n.Plugins, &JobPlugin{Type: p.Type, Configuration: p.Configuration.toMap()})
+	}
+	return n
+}
+
+func newXMLConfiguration(config map[string]string) *xmlConfiguration {
+	if len(config) == 0 {
+		return nil
+	}
+
+	c := &xmlConfiguration{}
+	for _, k := range sortedStringKeys(config) {
+		c.Entries = append(c.Entries, xmlEntry{Key: k, Value: config[k]})
+	}
+	return c
+}
+
+func (x *xmlConfiguration) toMap() map[string]string {
+	if x == nil || len(x.Entries) == 0 {
+		return nil
+	}
+
+	config := make(map[string]string, len(x.Entries))
+	for _, e := range x.Entries {
+		config[e.Key] = e.Value
+	}
+	return config
+}
+
+func nilIfEmpty(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
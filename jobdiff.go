@@ -0,0 +1,417 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// JobChangeKind is the kind of a single change between two job definitions
+type JobChangeKind string
+
+const (
+	JobChangeAdded   JobChangeKind = "added"
+	JobChangeRemoved JobChangeKind = "removed"
+	JobChangeChanged JobChangeKind = "changed"
+)
+
+// JobChange is a single semantic change between two job definitions
+type JobChange struct {
+	Kind JobChangeKind
+	// Path locates the change using the YAML keys, e.g. options[env].value or sequence.commands[3].exec
+	Path string
+	// Description is the change in words, e.g. option `env` default changed
+	Description string
+	Old         string
+	New         string
+}
+
+// JobDiff is the list of semantic changes between two job definitions
+type JobDiff struct {
+	Job     string
+	Changes []*JobChange
+}
+
+// Empty reports whether the definitions are equivalent
+func (d *JobDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String renders the changes as a text report
+func (d *JobDiff) String() string {
+	var b strings.Builder
+	if d.Empty() {
+		fmt.Fprintf(&b, "%s: no changes\n", d.Job)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%s: %d change(s)\n", d.Job, len(d.Changes))
+	for _, change := range d.Changes {
+		b.WriteString("  " + change.String() + "\n")
+	}
+	return b.String()
+}
+
+// String renders the change on a single line
+func (c *JobChange) String() string {
+	switch c.Kind {
+	case JobChangeAdded:
+		return "+ " + c.Description
+	case JobChangeRemoved:
+		return "- " + c.Description
+	}
+	return fmt.Sprintf("~ %s: %q -> %q", c.Description, c.Old, c.New)
+}
+
+// DiffJobDefinitions compares two job definitions after normalizing them, so that key ordering,
+// identifiers and fields left at their default values are not reported as changes
+func DiffJobDefinitions(old, new *JobDefinition) (*JobDiff, error) {
+	a, err := normalizeJobDefinition(old)
+	if err != nil {
+		return nil, err
+	}
+	b, err := normalizeJobDefinition(new)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &JobDiff{Job: joinGroupName(new.Group, new.Name)}
+
+	d.diffFields("", "", *a, *b)
+	d.diffRetry(a.Retry, b.Retry)
+	d.diffOptions(a.Options, b.Options)
+	d.diffNodeFilter("nodefilters", "node filter", a.NodeFilter, b.NodeFilter)
+	d.diffSchedule(a.Schedule, b.Schedule)
+	d.diffFields("sequence", "workflow", a.Sequence, b.Sequence)
+	d.diffSteps("sequence.commands", "step", a.Sequence.Commands, b.Sequence.Commands)
+	d.diffNotifications(a.Notification, b.Notification)
+	d.diffPlugin("orchestrator", "orchestrator", a.Orchestrator, b.Orchestrator)
+
+	return d, nil
+}
+
+// DiffDefinition compares a job on the server with a local definition
+func (j *Jobs) DiffDefinition(ctx context.Context, id string, def *JobDefinition) (*JobDiff, error) {
+	current, err := j.GetJobDefinitionWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return DiffJobDefinitions(current, def)
+}
+
+func (d *JobDiff) add(kind JobChangeKind, path, description, old, new string) {
+	d.Changes = append(d.Changes, &JobChange{
+		Kind:        kind,
+		Path:        path,
+		Description: description,
+		Old:         old,
+		New:         new,
+	})
+}
+
+// fieldLabels are the words used for yaml keys that read poorly on their own
+var fieldLabels = map[string]string{
+	"exec":                       "command",
+	"value":                      "default",
+	"args":                       "arguments",
+	"scripturl":                  "script url",
+	"scriptfile":                 "script file",
+	"loglevel":                   "log level",
+	"loglimit":                   "log limit",
+	"keepgoing":                  "keep going",
+	"threadcount":                "thread count",
+	"multivalued":                "multi-valued",
+	"valuesUrl":                  "values url",
+	"executionEnabled":           "execution enabled",
+	"scheduleEnabled":            "schedule enabled",
+	"multipleExecutions":         "multiple executions",
+	"nodesSelectedByDefault":     "nodes selected by default",
+	"notifyAvgDurationThreshold": "average duration threshold",
+	"timeZone":                   "time zone",
+}
+
+// diffFields compares the scalar, string slice and string map fields of two structs of the same type,
+// naming them by their yaml keys.  Nested structs are left to the caller.
+func (d *JobDiff) diffFields(path, subject string, a, b interface{}) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == reflect.Ptr {
+		if va.IsNil() {
+			va = reflect.New(va.Type().Elem())
+		}
+		if vb.IsNil() {
+			vb = reflect.New(vb.Type().Elem())
+		}
+		va, vb = va.Elem(), vb.Elem()
+	}
+
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" || key == "id" || key == "uuid" {
+			continue
+		}
+
+		old, ok := formatField(va.Field(i))
+		if !ok {
+			continue
+		}
+		new, _ := formatField(vb.Field(i))
+		if old == new {
+			continue
+		}
+
+		label := key
+		if l, ok := fieldLabels[key]; ok {
+			label = l
+		}
+		description := label + " changed"
+		if subject != "" {
+			description = subject + " " + description
+		}
+
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		d.add(JobChangeChanged, fieldPath, description, old, new)
+	}
+}
+
+// formatField renders the value as text, returning false for kinds that diffFields skips
+func formatField(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.Type().Elem().Kind() != reflect.Bool {
+			return "", false
+		}
+		if v.IsNil() {
+			return "", true
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int:
+		return fmt.Sprint(v.Interface()), true
+	case reflect.Slice:
+		if values, ok := v.Interface().([]string); ok {
+			return strings.Join(values, ","), true
+		}
+	case reflect.Map:
+		if m, ok := v.Interface().(map[string]string); ok {
+			var pairs []string
+			for _, k := range sortedStringKeys(m) {
+				pairs = append(pairs, k+"="+m[k])
+			}
+			return strings.Join(pairs, ","), true
+		}
+	}
+	return "", false
+}
+
+func (d *JobDiff) diffRetry(a, b *JobRetry) {
+	var old, new JobRetry
+	if a != nil {
+		old = *a
+	}
+	if b != nil {
+		new = *b
+	}
+	if old.Retry != new.Retry {
+		d.add(JobChangeChanged, "retry.retry", "retry count changed", old.Retry, new.Retry)
+	}
+	if old.Delay != new.Delay {
+		d.add(JobChangeChanged, "retry.delay", "retry delay changed", old.Delay, new.Delay)
+	}
+}
+
+func (d *JobDiff) diffOptions(a, b JobOptions) {
+	old := make(map[string]*JobOption)
+	var oldOrder []string
+	for _, o := range a {
+		old[o.Name] = o
+		oldOrder = append(oldOrder, o.Name)
+	}
+
+	var newOrder []string
+	for _, o := range b {
+		path := "options[" + o.Name + "]"
+		subject := "option `" + o.Name + "`"
+		if existing, ok := old[o.Name]; ok {
+			d.diffFields(path, subject, existing, o)
+			newOrder = append(newOrder, o.Name)
+			continue
+		}
+		d.add(JobChangeAdded, path, subject+" added", "", "")
+	}
+
+	for _, o := range a {
+		if !containsOption(b, o.Name) {
+			d.add(JobChangeRemoved, "options["+o.Name+"]", "option `"+o.Name+"` removed", "", "")
+		}
+	}
+
+	// only report reordering of the options both sides have in common
+	var common []string
+	for _, name := range oldOrder {
+		if containsOption(b, name) {
+			common = append(common, name)
+		}
+	}
+	if strings.Join(common, ",") != strings.Join(newOrder, ",") {
+		d.add(JobChangeChanged, "options", "option order changed", strings.Join(common, ","), strings.Join(newOrder, ","))
+	}
+}
+
+func containsOption(options JobOptions, name string) bool {
+	for _, o := range options {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *JobDiff) diffNodeFilter(path, subject string, a, b *JobNodeFilter) {
+	if a == nil {
+		a = &JobNodeFilter{}
+	}
+	if b == nil {
+		b = &JobNodeFilter{}
+	}
+	if a.Filter != b.Filter {
+		d.add(JobChangeChanged, path+".filter", subject+" changed", a.Filter, b.Filter)
+	}
+	d.diffFields(path+".dispatch", subject+" dispatch", a.Dispatch, b.Dispatch)
+}
+
+func (d *JobDiff) diffSchedule(a, b *JobSchedule) {
+	var old, new string
+	if a != nil {
+		old = a.CronExpression()
+	}
+	if b != nil {
+		new = b.CronExpression()
+	}
+
+	switch {
+	case old == new:
+	case old == "":
+		d.add(JobChangeAdded, "schedule", "schedule added", "", new)
+	case new == "":
+		d.add(JobChangeRemoved, "schedule", "schedule removed", old, "")
+	default:
+		d.add(JobChangeChanged, "schedule", "schedule changed", old, new)
+	}
+}
+
+func (d *JobDiff) diffSteps(path, subject string, a, b []*JobStep) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		stepPath := fmt.Sprintf("%s[%d]", path, i+1)
+		stepSubject := fmt.Sprintf("%s %d", subject, i+1)
+
+		switch {
+		case i >= len(a):
+			d.add(JobChangeAdded, stepPath, stepSubject+" added", "", string(b[i].Kind()))
+		case i >= len(b):
+			d.add(JobChangeRemoved, stepPath, stepSubject+" removed", string(a[i].Kind()), "")
+		default:
+			d.diffStep(stepPath, stepSubject, a[i], b[i])
+		}
+	}
+}
+
+func (d *JobDiff) diffStep(path, subject string, a, b *JobStep) {
+	if a.Kind() != b.Kind() {
+		d.add(JobChangeChanged, path, subject+" type changed", string(a.Kind()), string(b.Kind()))
+		return
+	}
+
+	d.diffFields(path, subject, a, b)
+
+	if a.JobRef != nil && b.JobRef != nil {
+		d.diffFields(path+".jobref", subject+" job reference", a.JobRef, b.JobRef)
+		if a.JobRef.NodeFilter != nil || b.JobRef.NodeFilter != nil {
+			d.diffNodeFilter(path+".jobref.nodefilters", subject+" job reference node filter", a.JobRef.NodeFilter, b.JobRef.NodeFilter)
+		}
+	}
+
+	switch {
+	case a.ErrorHandler == nil && b.ErrorHandler == nil:
+	case a.ErrorHandler == nil:
+		d.add(JobChangeAdded, path+".errorhandler", subject+" error handler added", "", string(b.ErrorHandler.Kind()))
+	case b.ErrorHandler == nil:
+		d.add(JobChangeRemoved, path+".errorhandler", subject+" error handler removed", string(a.ErrorHandler.Kind()), "")
+	default:
+		d.diffStep(path+".errorhandler", subject+" error handler", a.ErrorHandler, b.ErrorHandler)
+	}
+}
+
+func (d *JobDiff) diffNotifications(a, b *JobNotifications) {
+	if a == nil {
+		a = &JobNotifications{}
+	}
+	if b == nil {
+		b = &JobNotifications{}
+	}
+
+	events := []struct {
+		key      string
+		old, new *JobNotification
+	}{
+		{"onstart", a.OnStart, b.OnStart},
+		{"onsuccess", a.OnSuccess, b.OnSuccess},
+		{"onfailure", a.OnFailure, b.OnFailure},
+		{"onavgduration", a.OnAvgDuration, b.OnAvgDuration},
+		{"onretryablefailure", a.OnRetryableFailure, b.OnRetryableFailure},
+	}
+
+	for _, event := range events {
+		path := "notification." + event.key
+		subject := event.key + " notification"
+
+		old, new := event.old, event.new
+		if old == nil {
+			old = &JobNotification{}
+		}
+		if new == nil {
+			new = &JobNotification{}
+		}
+
+		d.diffFields(path+".email", subject+" email", old.Email, new.Email)
+		if old.URLs != new.URLs {
+			d.add(JobChangeChanged, path+".urls", subject+" webhook changed", old.URLs, new.URLs)
+		}
+
+		oldPlugins, newPlugins := marshalPlugins(old.Plugins), marshalPlugins(new.Plugins)
+		if oldPlugins != newPlugins {
+			d.add(JobChangeChanged, path+".plugin", subject+" plugins changed", oldPlugins, newPlugins)
+		}
+	}
+}
+
+func marshalPlugins(plugins JobPlugins) string {
+	var rendered []string
+	for _, p := range plugins {
+		bs, _ := yaml.Marshal(p)
+		rendered = append(rendered, strings.TrimSpace(string(bs)))
+	}
+	sort.Strings(rendered)
+	return strings.Join(rendered, "\n")
+}
+
+func (d *JobDiff) diffPlugin(path, subject string, a, b *JobPlugin) {
+	switch {
+	case a == nil && b == nil:
+	case a == nil:
+		d.add(JobChangeAdded, path, subject+" added", "", b.Type)
+	case b == nil:
+		d.add(JobChangeRemoved, path, subject+" removed", a.Type, "")
+	default:
+		d.diffFields(path, subject, a, b)
+	}
+}
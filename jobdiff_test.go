@@ -0,0 +1,148 @@
+package rundeck_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func TestDiffJobDefinitions(t *testing.T) {
+	old, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, []byte(`- id: 11111111-1111-1111-1111-111111111111
+  uuid: 11111111-1111-1111-1111-111111111111
+  name: deploy
+  group: ops/web
+  executionEnabled: true
+  loglevel: INFO
+  options:
+  - name: env
+    value: dev
+  - name: region
+  schedule:
+    month: '*'
+    time: {hour: '2', minute: '0', seconds: '0'}
+    weekday: {day: '*'}
+    year: '*'
+  sequence:
+    keepgoing: false
+    strategy: node-first
+    commands:
+    - exec: echo one
+    - exec: echo two
+    - exec: echo three
+`))
+	if err != nil {
+		t.Fatal("failed to parse yaml", err)
+	}
+
+	// key ordering, missing identifiers and defaulted fields are not changes
+	new, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, []byte(`- sequence:
+    commands:
+    - exec: echo one
+    - exec: echo two
+    - exec: echo 3
+    - script: echo four
+  group: ops/web
+  name: deploy
+  schedule:
+    crontab: 0 0 3 ? * * *
+  options:
+  - name: env
+    value: prod
+  - name: zone
+`))
+	if err != nil {
+		t.Fatal("failed to parse yaml", err)
+	}
+
+	diff, err := rundeck.DiffJobDefinitions(old[0], new[0])
+	if err != nil {
+		t.Fatal("failed to diff", err)
+	}
+
+	expected := []string{
+		"~ option `env` default changed: \"dev\" -> \"prod\"",
+		"+ option `zone` added",
+		"- option `region` removed",
+		"~ schedule changed: \"0 0 2 ? * * *\" -> \"0 0 3 ? * * *\"",
+		"~ step 3 command changed: \"echo three\" -> \"echo 3\"",
+		"+ step 4 added",
+	}
+
+	var actual []string
+	for _, change := range diff.Changes {
+		actual = append(actual, change.String())
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected changes:\n%s\nexpected:\n%s\n", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+	if diff.Changes[4].Path != "sequence.commands[3].exec" {
+		t.Errorf("unexpected path: %s\n", diff.Changes[4].Path)
+	}
+	if !strings.HasPrefix(diff.String(), "ops/web/deploy: 6 change(s)\n") {
+		t.Errorf("unexpected report:\n%s", diff)
+	}
+
+	same, err := rundeck.DiffJobDefinitions(old[0], old[0])
+	if err != nil {
+		t.Fatal("failed to diff", err)
+	}
+	if !same.Empty() {
+		t.Errorf("expected no changes:\n%s", same)
+	}
+}
+
+func TestDiffJobDefinitionsDispatchDefaults(t *testing.T) {
+	exported, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, []byte(`- id: 11111111-1111-1111-1111-111111111111
+  uuid: 11111111-1111-1111-1111-111111111111
+  name: deploy
+  nodefilters:
+    dispatch:
+      excludePrecedence: true
+      keepgoing: false
+      rankOrder: ascending
+      successOnEmptyNodeFilter: false
+      threadcount: '1'
+    filter: 'tags: web'
+  sequence:
+    commands:
+    - exec: deploy.sh
+`))
+	if err != nil {
+		t.Fatal("failed to parse yaml", err)
+	}
+
+	built, err := rundeck.NewJob("deploy").Step(rundeck.Command("deploy.sh")).NodeFilter("tags: web").Build()
+	if err != nil {
+		t.Fatal("failed to build job", err)
+	}
+
+	diff, err := rundeck.DiffJobDefinitions(exported[0], built)
+	if err != nil {
+		t.Fatal("failed to diff", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected the defaulted dispatch not to be a change:\n%s", diff)
+	}
+
+	// a partial dispatch only changes the fields it sets
+	built.NodeFilter.Dispatch = &rundeck.JobDispatch{ThreadCount: "4"}
+	diff, err = rundeck.DiffJobDefinitions(exported[0], built)
+	if err != nil {
+		t.Fatal("failed to diff", err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "nodefilters.dispatch.threadcount" {
+		t.Errorf("expected only the thread count to change:\n%s", diff)
+	}
+
+	// an explicit false is a change from Rundeck's default
+	exclude := false
+	built.NodeFilter.Dispatch = &rundeck.JobDispatch{ExcludePrecedence: &exclude}
+	diff, err = rundeck.DiffJobDefinitions(exported[0], built)
+	if err != nil {
+		t.Fatal("failed to diff", err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "nodefilters.dispatch.excludePrecedence" || diff.Changes[0].New != "false" {
+		t.Errorf("expected excludePrecedence to change to false:\n%s", diff)
+	}
+}
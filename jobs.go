@@ -2,12 +2,12 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"math"
 	"net/url"
 	"strconv"
 	"strings"
@@ -181,8 +181,8 @@ type UploadFileResponse struct {
 
 // UploadedFilesResponse returns the files uploaded for a particular job
 type UploadedFilesResponse struct {
-	PagingInfo
-	File []*FileOption `json:"files"`
+	PagingInfo `json:"paging"`
+	File       []*FileOption `json:"files"`
 }
 
 // FileOption is the metadata about a file that was uploaded for a job option
@@ -212,12 +212,17 @@ func (c *Client) Jobs() *Jobs {
 
 // List returns a list of jobs
 func (j *Jobs) List(project string, input *ListJobsInput) ([]*Job, error) {
+	return j.ListWithContext(context.Background(), project, input)
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (j *Jobs) ListWithContext(ctx context.Context, project string, input *ListJobsInput) ([]*Job, error) {
 	uri, err := j.urlEncodeListInput(j.c.RundeckAddr+"/project/"+project+"/jobs", input)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := j.c.checkResponseOK(j.c.get(uri))
+	res, err := j.c.checkResponseOK(j.c.get(ctx, uri))
 	if err != nil {
 		return nil, err
 	}
@@ -229,6 +234,11 @@ func (j *Jobs) List(project string, input *ListJobsInput) ([]*Job, error) {
 
 // Run will execute a job
 func (j *Jobs) Run(jobID string, input *RunJobInput) (*Execution, error) {
+	return j.RunWithContext(context.Background(), jobID, input)
+}
+
+// RunWithContext is the same as Run with the addition of the ability to pass a context.
+func (j *Jobs) RunWithContext(ctx context.Context, jobID string, input *RunJobInput) (*Execution, error) {
 	uri := j.c.RundeckAddr + "/job/" + jobID + "/run"
 
 	var body io.Reader
@@ -240,7 +250,7 @@ func (j *Jobs) Run(jobID string, input *RunJobInput) (*Execution, error) {
 		body = bytes.NewReader(bs)
 	}
 
-	res, err := j.c.checkResponseOK(j.c.post(uri, body))
+	res, err := j.c.checkResponseOK(j.c.post(ctx, uri, body))
 	if err != nil {
 		return nil, err
 	}
@@ -252,6 +262,11 @@ func (j *Jobs) Run(jobID string, input *RunJobInput) (*Execution, error) {
 
 // Retry retries a job based on an execution id
 func (j *Jobs) Retry(jobID string, execID int64, input *RetryJobInput) (*Execution, error) {
+	return j.RetryWithContext(context.Background(), jobID, execID, input)
+}
+
+// RetryWithContext is the same as Retry with the addition of the ability to pass a context.
+func (j *Jobs) RetryWithContext(ctx context.Context, jobID string, execID int64, input *RetryJobInput) (*Execution, error) {
 	uri := j.c.RundeckAddr + "/job/" + jobID + "/retry/" + strconv.FormatInt(execID, 10)
 
 	var body io.Reader
@@ -263,7 +278,7 @@ func (j *Jobs) Retry(jobID string, execID int64, input *RetryJobInput) (*Executi
 		body = bytes.NewReader(bs)
 	}
 
-	res, err := j.c.checkResponseOK(j.c.post(uri, body))
+	res, err := j.c.checkResponseOK(j.c.post(ctx, uri, body))
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +290,11 @@ func (j *Jobs) Retry(jobID string, execID int64, input *RetryJobInput) (*Executi
 
 // Export exports a projects jobs defintions
 func (j *Jobs) Export(project string, input *ExportJobsInput) ([]byte, error) {
+	return j.ExportWithContext(context.Background(), project, input)
+}
+
+// ExportWithContext is the same as Export with the addition of the ability to pass a context.
+func (j *Jobs) ExportWithContext(ctx context.Context, project string, input *ExportJobsInput) ([]byte, error) {
 	rawURL := j.c.RundeckAddr + "/project/" + project + "/jobs/export"
 
 	uri, err := url.Parse(rawURL)
@@ -308,7 +328,7 @@ func (j *Jobs) Export(project string, input *ExportJobsInput) ([]byte, error) {
 
 	uri.RawQuery = query.Encode()
 
-	res, err := j.c.checkResponseOK(j.c.get(uri.String()))
+	res, err := j.c.checkResponseOK(j.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -319,6 +339,11 @@ func (j *Jobs) Export(project string, input *ExportJobsInput) ([]byte, error) {
 
 // Import imports job definitions
 func (j *Jobs) Import(project string, input *ImportJobsInput) (*ImportJobsResponse, error) {
+	return j.ImportWithContext(context.Background(), project, input)
+}
+
+// ImportWithContext is the same as Import with the addition of the ability to pass a context.
+func (j *Jobs) ImportWithContext(ctx context.Context, project string, input *ImportJobsInput) (*ImportJobsResponse, error) {
 	if input == nil {
 		return nil, fmt.Errorf("input cannot be nil as ImportJobsInput.RawContent is required to import anything")
 	}
@@ -349,7 +374,7 @@ func (j *Jobs) Import(project string, input *ImportJobsInput) (*ImportJobsRespon
 
 	uri.RawQuery = query.Encode()
 
-	res, err := j.c.checkResponseOK(j.c.postWithAdditionalHeaders(uri.String(), map[string]string{"Content-Type": contentType}, bytes.NewReader(input.RawContent)))
+	res, err := j.c.checkResponseOK(j.c.postWithAdditionalHeaders(ctx, uri.String(), map[string]string{"Content-Type": contentType}, bytes.NewReader(input.RawContent)))
 	if err != nil {
 		return nil, err
 	}
@@ -361,6 +386,11 @@ func (j *Jobs) Import(project string, input *ImportJobsInput) (*ImportJobsRespon
 
 // GetDefinition returns a job definition as a slice of bytces in either xml or yaml
 func (j *Jobs) GetDefinition(id string, format *JobFormat) ([]byte, error) {
+	return j.GetDefinitionWithContext(context.Background(), id, format)
+}
+
+// GetDefinitionWithContext is the same as GetDefinition with the addition of the ability to pass a context.
+func (j *Jobs) GetDefinitionWithContext(ctx context.Context, id string, format *JobFormat) ([]byte, error) {
 	rawURL := j.c.RundeckAddr + "/job/" + id
 
 	returnFormat := JobFormatXML
@@ -377,7 +407,7 @@ func (j *Jobs) GetDefinition(id string, format *JobFormat) ([]byte, error) {
 	query.Add("format", string(returnFormat))
 	uri.RawQuery = query.Encode()
 
-	res, err := j.c.checkResponseOK(j.c.get(uri.String()))
+	res, err := j.c.checkResponseOK(j.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -388,9 +418,14 @@ func (j *Jobs) GetDefinition(id string, format *JobFormat) ([]byte, error) {
 
 // DeleteDefinition deletes a job definition
 func (j *Jobs) DeleteDefinition(id string) error {
+	return j.DeleteDefinitionWithContext(context.Background(), id)
+}
+
+// DeleteDefinitionWithContext is the same as DeleteDefinition with the addition of the ability to pass a context.
+func (j *Jobs) DeleteDefinitionWithContext(ctx context.Context, id string) error {
 	rawURL := j.c.RundeckAddr + "/job/" + id
 
-	res, err := j.c.checkResponseNoContent(j.c.delete(rawURL, nil))
+	res, err := j.c.checkResponseNoContent(j.c.delete(ctx, rawURL, nil))
 	if err != nil {
 		return err
 	}
@@ -401,6 +436,11 @@ func (j *Jobs) DeleteDefinition(id string) error {
 
 // BulkDelete deletes jobs in bulk by ID
 func (j *Jobs) BulkDelete(input *BulkModifyInput) (*BulkModifyResponse, error) {
+	return j.BulkDeleteWithContext(context.Background(), input)
+}
+
+// BulkDeleteWithContext is the same as BulkDelete with the addition of the ability to pass a context.
+func (j *Jobs) BulkDeleteWithContext(ctx context.Context, input *BulkModifyInput) (*BulkModifyResponse, error) {
 	if input == nil {
 		return nil, fmt.Errorf("bulk delete input cannot be nil")
 	}
@@ -412,7 +452,7 @@ func (j *Jobs) BulkDelete(input *BulkModifyInput) (*BulkModifyResponse, error) {
 		return nil, err
 	}
 
-	res, err := j.c.checkResponseOK(j.c.delete(rawURL, bytes.NewReader(bs)))
+	res, err := j.c.checkResponseOK(j.c.delete(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +463,11 @@ func (j *Jobs) BulkDelete(input *BulkModifyInput) (*BulkModifyResponse, error) {
 
 // ToggleExecutionsOrSchedules toggles the executions or schedules of the supplied job
 func (j *Jobs) ToggleExecutionsOrSchedules(id string, enabled bool, toggleKind ToggleKind) (*SuccessResponse, error) {
+	return j.ToggleExecutionsOrSchedulesWithContext(context.Background(), id, enabled, toggleKind)
+}
+
+// ToggleExecutionsOrSchedulesWithContext is the same as ToggleExecutionsOrSchedules with the addition of the ability to pass a context.
+func (j *Jobs) ToggleExecutionsOrSchedulesWithContext(ctx context.Context, id string, enabled bool, toggleKind ToggleKind) (*SuccessResponse, error) {
 	if toggleKind != ToggleKindExecution && toggleKind != ToggleKindSchedule {
 		return nil, errors.New(`toggleKind must be "execution" or "schedule"`)
 	}
@@ -435,7 +480,7 @@ func (j *Jobs) ToggleExecutionsOrSchedules(id string, enabled bool, toggleKind T
 		rawURL += "/disable"
 	}
 
-	res, err := j.c.checkResponseOK(j.c.post(rawURL, nil))
+	res, err := j.c.checkResponseOK(j.c.post(ctx, rawURL, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -447,6 +492,11 @@ func (j *Jobs) ToggleExecutionsOrSchedules(id string, enabled bool, toggleKind T
 
 // BulkToggleExecutionsOrSchedules toggles the execution or scheudle value of the suppplied job ids
 func (j *Jobs) BulkToggleExecutionsOrSchedules(input *BulkModifyInput, enabled bool, toggleKind ToggleKind) (*BulkModifyResponse, error) {
+	return j.BulkToggleExecutionsOrSchedulesWithContext(context.Background(), input, enabled, toggleKind)
+}
+
+// BulkToggleExecutionsOrSchedulesWithContext is the same as BulkToggleExecutionsOrSchedules with the addition of the ability to pass a context.
+func (j *Jobs) BulkToggleExecutionsOrSchedulesWithContext(ctx context.Context, input *BulkModifyInput, enabled bool, toggleKind ToggleKind) (*BulkModifyResponse, error) {
 	if input == nil {
 		return nil, fmt.Errorf("input cannot be nil")
 	}
@@ -468,7 +518,7 @@ func (j *Jobs) BulkToggleExecutionsOrSchedules(input *BulkModifyInput, enabled b
 		return nil, err
 	}
 
-	res, err := j.c.checkResponseOK(j.c.post(rawURL, bytes.NewReader(bs)))
+	res, err := j.c.checkResponseOK(j.c.post(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -480,9 +530,14 @@ func (j *Jobs) BulkToggleExecutionsOrSchedules(input *BulkModifyInput, enabled b
 
 // GetMetadata returns basic information about a job
 func (j *Jobs) GetMetadata(id string) (*Job, error) {
+	return j.GetMetadataWithContext(context.Background(), id)
+}
+
+// GetMetadataWithContext is the same as GetMetadata with the addition of the ability to pass a context.
+func (j *Jobs) GetMetadataWithContext(ctx context.Context, id string) (*Job, error) {
 	rawURL := j.c.RundeckAddr + "/job/" + id + "/info"
 
-	res, err := j.c.checkResponseOK(j.c.get(rawURL))
+	res, err := j.c.checkResponseOK(j.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -494,6 +549,11 @@ func (j *Jobs) GetMetadata(id string) (*Job, error) {
 
 // UploadFileForJobOption uploads a file to rundeck for a job option and returns the file key
 func (j *Jobs) UploadFileForJobOption(id, optionName string, content []byte, fileName *string) (*UploadFileResponse, error) {
+	return j.UploadFileForJobOptionWithContext(context.Background(), id, optionName, content, fileName)
+}
+
+// UploadFileForJobOptionWithContext is the same as UploadFileForJobOption with the addition of the ability to pass a context.
+func (j *Jobs) UploadFileForJobOptionWithContext(ctx context.Context, id, optionName string, content []byte, fileName *string) (*UploadFileResponse, error) {
 	rawURL := j.c.RundeckAddr + "/job/" + id + "/input/file"
 
 	uri, err := url.Parse(rawURL)
@@ -512,7 +572,7 @@ func (j *Jobs) UploadFileForJobOption(id, optionName string, content []byte, fil
 		"Content-Type": "application/octet-stream",
 	}
 
-	res, err := j.c.checkResponseOK(j.c.postWithAdditionalHeaders(uri.String(), headers, bytes.NewReader(content)))
+	res, err := j.c.checkResponseOK(j.c.postWithAdditionalHeaders(ctx, uri.String(), headers, bytes.NewReader(content)))
 	if err != nil {
 		return nil, err
 	}
@@ -524,6 +584,19 @@ func (j *Jobs) UploadFileForJobOption(id, optionName string, content []byte, fil
 
 // ListFilesUploadedForJob returns files that were uploaded for a particular job
 func (j *Jobs) ListFilesUploadedForJob(id string, fileState *FileState, max *int) (*UploadedFilesResponse, error) {
+	return j.ListFilesUploadedForJobWithContext(context.Background(), id, fileState, max)
+}
+
+// ListFilesUploadedForJobWithContext is the same as ListFilesUploadedForJob with the addition of the ability to pass a context.
+func (j *Jobs) ListFilesUploadedForJobWithContext(ctx context.Context, id string, fileState *FileState, max *int) (*UploadedFilesResponse, error) {
+	var paging *PagingInfo
+	if max != nil {
+		paging = &PagingInfo{Max: *max}
+	}
+	return j.listFilesUploadedForJob(ctx, id, fileState, paging)
+}
+
+func (j *Jobs) listFilesUploadedForJob(ctx context.Context, id string, fileState *FileState, paging *PagingInfo) (*UploadedFilesResponse, error) {
 	rawURL := j.c.RundeckAddr + "/job/" + id + "/input/files"
 
 	uri, err := url.Parse(rawURL)
@@ -535,12 +608,10 @@ func (j *Jobs) ListFilesUploadedForJob(id string, fileState *FileState, max *int
 	if fileState != nil {
 		query.Add("fileState", string(*fileState))
 	}
-	if max != nil {
-		query.Add("max", strconv.Itoa(math.MaxInt8))
-	}
+	encodePagingInfo(query, paging)
 	uri.RawQuery = query.Encode()
 
-	res, err := j.c.checkResponseOK(j.c.get(uri.String()))
+	res, err := j.c.checkResponseOK(j.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -552,9 +623,14 @@ func (j *Jobs) ListFilesUploadedForJob(id string, fileState *FileState, max *int
 
 // FileInfo returns information about an uploaded file
 func (j *Jobs) FileInfo(id string) (*FileOption, error) {
+	return j.FileInfoWithContext(context.Background(), id)
+}
+
+// FileInfoWithContext is the same as FileInfo with the addition of the ability to pass a context.
+func (j *Jobs) FileInfoWithContext(ctx context.Context, id string) (*FileOption, error) {
 	rawURL := j.c.RundeckAddr + "/jobs/file/" + id
 
-	res, err := j.c.checkResponseOK(j.c.get(rawURL))
+	res, err := j.c.checkResponseOK(j.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,274 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// JobSyncAction is what Jobs.Sync does to a single job
+type JobSyncAction string
+
+const (
+	// JobSyncActionCreate imports a local definition that matches no job in the project
+	JobSyncActionCreate JobSyncAction = "create"
+	// JobSyncActionUpdate imports a local definition over the job it matches, when they differ
+	JobSyncActionUpdate JobSyncAction = "update"
+	// JobSyncActionDelete removes a job without a local definition, only planned when pruning
+	JobSyncActionDelete JobSyncAction = "delete"
+)
+
+// JobSyncOptions are the optional parameters for Jobs.Sync
+type JobSyncOptions struct {
+	// DryRun computes and prints the plan without changing anything
+	DryRun bool
+
+	// Prune deletes jobs in the project that have no local definition
+	Prune bool
+
+	// Output receives the human readable plan.  Nothing is printed when it is nil.
+	Output io.Writer
+}
+
+// JobSyncChange is a single planned change
+type JobSyncChange struct {
+	Action JobSyncAction
+	// ID is the id of the existing job for updates and deletes
+	ID    string
+	Group string
+	Name  string
+	// Desired is the local definition for creates and updates
+	Desired *JobDefinition
+	// Current is the server's definition for updates and deletes
+	Current *JobDefinition
+	// Diff lists what an update changes
+	Diff *JobDiff
+}
+
+// JobSyncPlan is the set of changes needed to make a project match the local definitions
+type JobSyncPlan struct {
+	Project   string
+	Changes   []*JobSyncChange
+	Unchanged []string
+}
+
+// JobSyncResult is the outcome of Jobs.Sync.  Imported and Deleted are nil when nothing was sent.
+type JobSyncResult struct {
+	Plan     *JobSyncPlan
+	Imported *ImportJobsResponse
+	Deleted  *BulkModifyResponse
+}
+
+// Count returns the number of changes with the given action
+func (p *JobSyncPlan) Count(action JobSyncAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the plan for people to review
+func (p *JobSyncPlan) String() string {
+	var b strings.Builder
+	writeSyncPlanSummary(&b, "project "+p.Project,
+		p.Count(JobSyncActionCreate), p.Count(JobSyncActionUpdate), p.Count(JobSyncActionDelete), len(p.Unchanged))
+
+	for _, change := range p.Changes {
+		fmt.Fprintf(&b, "  %s %s", syncActionSymbol(string(change.Action)), joinGroupName(change.Group, change.Name))
+		if change.ID != "" {
+			fmt.Fprintf(&b, " (%s)", change.ID)
+		}
+		b.WriteString("\n")
+		if change.Diff != nil {
+			for _, c := range change.Diff.Changes {
+				b.WriteString("      " + c.String() + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Sync reconciles the jobs in a project with the local definitions, like a plan and apply.
+//
+// Local definitions are matched to existing jobs by uuid when they have one, otherwise by group and name.
+// Creates and updates are sent in a single Import using DuplicateOptionUpdate, and when opts.Prune is set
+// jobs without a local definition are removed with BulkDelete.
+func (j *Jobs) Sync(ctx context.Context, project string, definitions []*JobDefinition, opts *JobSyncOptions) (*JobSyncResult, error) {
+	if opts == nil {
+		opts = &JobSyncOptions{}
+	}
+
+	plan, err := j.planSync(ctx, project, definitions, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, plan.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &JobSyncResult{Plan: plan}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	var imports []*JobDefinition
+	var deletes []string
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case JobSyncActionCreate, JobSyncActionUpdate:
+			imports = append(imports, change.Desired)
+		case JobSyncActionDelete:
+			deletes = append(deletes, change.ID)
+		}
+	}
+
+	if len(imports) > 0 {
+		input, err := NewImportJobsInput(imports...)
+		if err != nil {
+			return result, err
+		}
+		input.DuplicateOption = DuplicateOptionUpdate
+
+		result.Imported, err = j.ImportWithContext(ctx, project, input)
+		if err != nil {
+			return result, err
+		}
+		if len(result.Imported.Failed) > 0 {
+			var failures []string
+			for _, failed := range result.Imported.Failed {
+				failures = append(failures, joinGroupName(failed.Group, failed.Name)+": "+failed.Description)
+			}
+			return result, fmt.Errorf("failed to import %d job(s): %s", len(failures), strings.Join(failures, "; "))
+		}
+	}
+
+	if len(deletes) > 0 {
+		var err error
+		result.Deleted, err = j.BulkDeleteWithContext(ctx, &BulkModifyInput{IDs: deletes})
+		if err != nil {
+			return result, err
+		}
+		if !result.Deleted.AllSuccessful {
+			return result, fmt.Errorf("failed to delete %d job(s)", len(result.Deleted.Failed))
+		}
+	}
+
+	return result, nil
+}
+
+func (j *Jobs) planSync(ctx context.Context, project string, definitions []*JobDefinition, prune bool) (*JobSyncPlan, error) {
+	local := make(map[string]bool)
+	for _, def := range definitions {
+		if err := ValidateJobDefinition(def); err != nil {
+			return nil, err
+		}
+		key := joinGroupName(def.Group, def.Name)
+		if local[key] {
+			return nil, fmt.Errorf("job %s is defined more than once", key)
+		}
+		local[key] = true
+	}
+
+	jobs, err := j.ListWithContext(ctx, project, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*JobDefinition)
+	byName := make(map[string]*JobDefinition)
+	for _, job := range jobs {
+		def, err := j.GetJobDefinitionWithContext(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		if def.UUID == "" {
+			def.UUID = job.ID
+		}
+		byID[def.UUID] = def
+		byName[joinGroupName(def.Group, def.Name)] = def
+	}
+
+	plan := &JobSyncPlan{Project: project}
+	matched := make(map[string]bool)
+	for _, def := range definitions {
+		current := byID[def.UUID]
+		if current == nil {
+			current = byName[joinGroupName(def.Group, def.Name)]
+		}
+
+		if current == nil {
+			plan.Changes = append(plan.Changes, &JobSyncChange{
+				Action:  JobSyncActionCreate,
+				Group:   def.Group,
+				Name:    def.Name,
+				Desired: def,
+			})
+			continue
+		}
+		if matched[current.UUID] {
+			return nil, fmt.Errorf("job %s matches %s (%s), which another definition already matches", joinGroupName(def.Group, def.Name), joinGroupName(current.Group, current.Name), current.UUID)
+		}
+		matched[current.UUID] = true
+
+		diff, err := DiffJobDefinitions(current, def)
+		if err != nil {
+			return nil, err
+		}
+		if diff.Empty() {
+			plan.Unchanged = append(plan.Unchanged, joinGroupName(def.Group, def.Name))
+			continue
+		}
+
+		// update the matched job in place, even when it was matched by name
+		desired, err := cloneJobDefinition(def)
+		if err != nil {
+			return nil, err
+		}
+		desired.ID = current.UUID
+		desired.UUID = current.UUID
+
+		plan.Changes = append(plan.Changes, &JobSyncChange{
+			Action:  JobSyncActionUpdate,
+			ID:      current.UUID,
+			Group:   def.Group,
+			Name:    def.Name,
+			Desired: desired,
+			Current: current,
+			Diff:    diff,
+		})
+	}
+
+	if prune {
+		for id, current := range byID {
+			if matched[id] {
+				continue
+			}
+			plan.Changes = append(plan.Changes, &JobSyncChange{
+				Action:  JobSyncActionDelete,
+				ID:      id,
+				Group:   current.Group,
+				Name:    current.Name,
+				Current: current,
+			})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(a, b int) bool {
+		ca, cb := plan.Changes[a], plan.Changes[b]
+		if ca.Action != cb.Action {
+			return syncActionRank(string(ca.Action)) < syncActionRank(string(cb.Action))
+		}
+		return joinGroupName(ca.Group, ca.Name) < joinGroupName(cb.Group, cb.Name)
+	})
+	sort.Strings(plan.Unchanged)
+
+	return plan, nil
+}
@@ -0,0 +1,90 @@
+package rundeck_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func TestJobSync(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+	ctx := context.Background()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "JobSync"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("JobSync")
+
+	build := func(b *rundeck.JobBuilder) *rundeck.JobDefinition {
+		def, err := b.Build()
+		if err != nil {
+			t.Fatal("failed to build job", err)
+		}
+		return def
+	}
+
+	existing := []*rundeck.JobDefinition{
+		build(rundeck.NewJob("same").Group("ops").Step(rundeck.Command("uptime"))),
+		build(rundeck.NewJob("changed").Group("ops").Step(rundeck.Command("echo before"))),
+		build(rundeck.NewJob("orphan").Group("ops").Step(rundeck.Command("echo orphan"))),
+	}
+	if _, err := cli.Jobs().Sync(ctx, "JobSync", existing, nil); err != nil {
+		t.Fatal("failed to seed jobs", err)
+	}
+
+	desired := []*rundeck.JobDefinition{
+		build(rundeck.NewJob("same").Group("ops").Step(rundeck.Command("uptime"))),
+		build(rundeck.NewJob("changed").Group("ops").Step(rundeck.Command("echo after"))),
+		build(rundeck.NewJob("new").Group("ops").Step(rundeck.Command("echo new"))),
+	}
+
+	var out bytes.Buffer
+	result, err := cli.Jobs().Sync(ctx, "JobSync", desired, &rundeck.JobSyncOptions{DryRun: true, Prune: true, Output: &out})
+	if err != nil {
+		t.Fatal("failed to plan sync", err)
+	}
+	plan := result.Plan
+	if plan.Count(rundeck.JobSyncActionCreate) != 1 || plan.Count(rundeck.JobSyncActionUpdate) != 1 || plan.Count(rundeck.JobSyncActionDelete) != 1 || len(plan.Unchanged) != 1 {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+	for _, line := range []string{"1 to create, 1 to update, 1 to delete, 1 unchanged", "+ ops/new", "~ ops/changed", "- ops/orphan"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in the printed plan:\n%s", line, out.String())
+		}
+	}
+	if result.Imported != nil || result.Deleted != nil {
+		t.Error("a dry run should not change anything")
+	}
+
+	result, err = cli.Jobs().Sync(ctx, "JobSync", desired, &rundeck.JobSyncOptions{Prune: true})
+	if err != nil {
+		t.Fatal("failed to apply sync", err)
+	}
+	if len(result.Imported.Succeeded) != 2 || len(result.Deleted.Succeeded) != 1 {
+		t.Errorf("unexpected sync result: %+v %+v\n", result.Imported, result.Deleted)
+	}
+
+	result, err = cli.Jobs().Sync(ctx, "JobSync", desired, &rundeck.JobSyncOptions{DryRun: true, Prune: true})
+	if err != nil {
+		t.Fatal("failed to plan sync", err)
+	}
+	if len(result.Plan.Changes) != 0 || len(result.Plan.Unchanged) != 3 {
+		t.Errorf("expected the project to be in sync:\n%s", result.Plan)
+	}
+
+	// a definition matched by uuid and another matched by name cannot both update the same job
+	jobs, err := cli.Jobs().List("JobSync", &rundeck.ListJobsInput{JobExactFilter: "same"})
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("failed to find ops/same: %v %+v\n", err, jobs)
+	}
+	conflicting := []*rundeck.JobDefinition{
+		build(rundeck.NewJob("renamed").Group("ops").UUID(jobs[0].ID).Step(rundeck.Command("uptime"))),
+		build(rundeck.NewJob("same").Group("ops").Step(rundeck.Command("uptime"))),
+	}
+	if _, err := cli.Jobs().Sync(ctx, "JobSync", conflicting, &rundeck.JobSyncOptions{DryRun: true}); err == nil || !strings.Contains(err.Error(), "already matches") {
+		t.Errorf("expected definitions matching the same job to be refused, received %v\n", err)
+	}
+}
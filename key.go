@@ -1,6 +1,20 @@
 package rundeck
 
-import "encoding/json"
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+)
+
+// KeyContentType is the content type a key is stored with, which determines the type of key
+type KeyContentType string
+
+const (
+	KeyContentTypePassword   KeyContentType = "application/x-rundeck-data-password"
+	KeyContentTypePublicKey  KeyContentType = "application/pgp-keys"
+	KeyContentTypePrivateKey KeyContentType = "application/octet-stream"
+)
 
 // ListKeysResponse ...
 type ListKeysResponse struct {
@@ -41,9 +55,14 @@ func (c *Client) KeyStorage() *KeyStore {
 
 // List lists resources at the specified path
 func (k *KeyStore) List(path string) (*ListKeysResponse, error) {
+	return k.ListWithContext(context.Background(), path)
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (k *KeyStore) ListWithContext(ctx context.Context, path string) (*ListKeysResponse, error) {
 	rawURL := k.c.RundeckAddr + "/storage/keys/" + path + "/"
 
-	res, err := k.c.checkResponseOK(k.c.get(rawURL))
+	res, err := k.c.checkResponseOK(k.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -55,9 +74,14 @@ func (k *KeyStore) List(path string) (*ListKeysResponse, error) {
 
 // KeyMetadata returns the metadata about the stored key file
 func (k *KeyStore) KeyMetadata(path, file string) (*KeyMetadata, error) {
+	return k.KeyMetadataWithContext(context.Background(), path, file)
+}
+
+// KeyMetadataWithContext is the same as KeyMetadata with the addition of the ability to pass a context.
+func (k *KeyStore) KeyMetadataWithContext(ctx context.Context, path, file string) (*KeyMetadata, error) {
 	rawURL := k.c.RundeckAddr + "/storage/keys/" + path + "/" + file
 
-	res, err := k.c.checkResponseOK(k.c.get(rawURL))
+	res, err := k.c.checkResponseOK(k.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +91,72 @@ func (k *KeyStore) KeyMetadata(path, file string) (*KeyMetadata, error) {
 	return &metadata, json.NewDecoder(res.Body).Decode(&metadata)
 }
 
+// Create stores a new key.  The content type determines whether it is a password, public key or private key.
+func (k *KeyStore) Create(path, file string, contentType KeyContentType, content []byte) (*KeyResource, error) {
+	return k.CreateWithContext(context.Background(), path, file, contentType, content)
+}
+
+// CreateWithContext is the same as Create with the addition of the ability to pass a context.
+func (k *KeyStore) CreateWithContext(ctx context.Context, path, file string, contentType KeyContentType, content []byte) (*KeyResource, error) {
+	rawURL := k.c.RundeckAddr + "/storage/keys/" + path + "/" + file
+
+	res, err := k.c.checkResponseCreated(k.c.postWithAdditionalHeaders(ctx, rawURL, map[string]string{"Content-Type": string(contentType)}, bytes.NewReader(content)))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var resource KeyResource
+	return &resource, json.NewDecoder(res.Body).Decode(&resource)
+}
+
+// Update replaces the content of an existing key
+func (k *KeyStore) Update(path, file string, contentType KeyContentType, content []byte) (*KeyResource, error) {
+	return k.UpdateWithContext(context.Background(), path, file, contentType, content)
+}
+
+// UpdateWithContext is the same as Update with the addition of the ability to pass a context.
+func (k *KeyStore) UpdateWithContext(ctx context.Context, path, file string, contentType KeyContentType, content []byte) (*KeyResource, error) {
+	rawURL := k.c.RundeckAddr + "/storage/keys/" + path + "/" + file
+
+	res, err := k.c.checkResponseOK(k.c.putWithAdditionalHeaders(ctx, rawURL, map[string]string{"Content-Type": string(contentType)}, bytes.NewReader(content)))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var resource KeyResource
+	return &resource, json.NewDecoder(res.Body).Decode(&resource)
+}
+
+// PublicKeyContents returns the content of a public key.  Password and private key contents cannot be read.
+func (k *KeyStore) PublicKeyContents(path, file string) ([]byte, error) {
+	return k.PublicKeyContentsWithContext(context.Background(), path, file)
+}
+
+// PublicKeyContentsWithContext is the same as PublicKeyContents with the addition of the ability to pass a context.
+func (k *KeyStore) PublicKeyContentsWithContext(ctx context.Context, path, file string) ([]byte, error) {
+	rawURL := k.c.RundeckAddr + "/storage/keys/" + path + "/" + file
+
+	res, err := k.c.checkResponseOK(k.c.getWithAdditionalHeaders(ctx, rawURL, map[string]string{"Accept": string(KeyContentTypePublicKey)}))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return ioutil.ReadAll(res.Body)
+}
+
 // Delete deletes the file if it exists
 func (k *KeyStore) Delete(path, file string) error {
+	return k.DeleteWithContext(context.Background(), path, file)
+}
+
+// DeleteWithContext is the same as Delete with the addition of the ability to pass a context.
+func (k *KeyStore) DeleteWithContext(ctx context.Context, path, file string) error {
 	rawURL := k.c.RundeckAddr + "/storage/keys/" + path + "/" + file
 
-	_, err := k.c.checkResponseNoContent(k.c.delete(rawURL, nil))
+	_, err := k.c.checkResponseNoContent(k.c.delete(ctx, rawURL, nil))
 	if err != nil {
 		return err
 	}
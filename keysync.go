@@ -0,0 +1,366 @@
+package rundeck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SourceKey is a key that KeyStore.Sync mirrors into storage
+type SourceKey struct {
+	// Path is relative to the storage prefix being synced, e.g. app/db/password
+	Path        string
+	ContentType KeyContentType
+	Content     []byte
+}
+
+// KeySource supplies the keys that KeyStore.Sync mirrors into storage.  Implement it to back
+// a sync with a secrets manager.
+type KeySource interface {
+	Keys(ctx context.Context) ([]*SourceKey, error)
+}
+
+// KeySourceFunc adapts a function to a KeySource
+type KeySourceFunc func(ctx context.Context) ([]*SourceKey, error)
+
+// Keys calls f
+func (f KeySourceFunc) Keys(ctx context.Context) ([]*SourceKey, error) {
+	return f(ctx)
+}
+
+// DirKeySource is a KeySource reading every file below a local directory.  A file's path relative
+// to the directory is its path in storage.  Hidden files and directories are ignored.
+type DirKeySource struct {
+	Dir string
+
+	// ContentType decides what a file is stored as.  By default files ending in .pub are public keys,
+	// files holding a PEM private key are private keys and everything else is a password.
+	ContentType func(path string, content []byte) KeyContentType
+}
+
+// Keys reads the directory.  A single trailing newline is trimmed from passwords.
+func (d *DirKeySource) Keys(ctx context.Context) ([]*SourceKey, error) {
+	contentType := d.ContentType
+	if contentType == nil {
+		contentType = detectKeyContentType
+	}
+
+	var keys []*SourceKey
+	err := filepath.Walk(d.Dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if file != d.Dir && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(d.Dir, file)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		key := &SourceKey{Path: filepath.ToSlash(rel), Content: content}
+		key.ContentType = contentType(key.Path, content)
+		if key.ContentType == KeyContentTypePassword {
+			key.Content = bytes.TrimSuffix(bytes.TrimSuffix(content, []byte("\n")), []byte("\r"))
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	return keys, err
+}
+
+func detectKeyContentType(path string, content []byte) KeyContentType {
+	switch {
+	case strings.HasSuffix(path, ".pub"):
+		return KeyContentTypePublicKey
+	case bytes.Contains(content, []byte("PRIVATE KEY-----")):
+		return KeyContentTypePrivateKey
+	}
+	return KeyContentTypePassword
+}
+
+// keyTypeOf returns the key type Rundeck reports for a content type
+func keyTypeOf(contentType KeyContentType) string {
+	switch contentType {
+	case KeyContentTypePassword:
+		return "password"
+	case KeyContentTypePublicKey:
+		return "public"
+	case KeyContentTypePrivateKey:
+		return "private"
+	}
+	return string(contentType)
+}
+
+// KeySyncAction is what KeyStore.Sync does to a single key
+type KeySyncAction string
+
+const (
+	// KeySyncActionCreate stores a source key that is missing below the prefix
+	KeySyncActionCreate KeySyncAction = "create"
+	// KeySyncActionUpdate replaces a stored key that differs from the source, see KeySyncOptions.Overwrite
+	KeySyncActionUpdate KeySyncAction = "update"
+	// KeySyncActionDelete removes a stored key the source does not have, only planned when pruning
+	KeySyncActionDelete KeySyncAction = "delete"
+)
+
+// KeySyncOptions are the optional parameters for KeyStore.Sync
+type KeySyncOptions struct {
+	// DryRun computes and prints the plan without changing anything
+	DryRun bool
+
+	// Prune deletes keys below the prefix that the source does not have
+	Prune bool
+
+	// Overwrite updates every password and private key.  Their contents cannot be read back, so
+	// without it a change is only detected when the content type or size differs.
+	Overwrite bool
+
+	// Output receives the human readable plan.  Nothing is printed when it is nil.
+	Output io.Writer
+}
+
+// KeySyncChange is a single planned change
+type KeySyncChange struct {
+	Action KeySyncAction
+	// Path is relative to the prefix
+	Path string
+	// Key is the source key for creates and updates
+	Key *SourceKey
+	// Current is the stored metadata for updates and deletes
+	Current *KeyMetadata
+	// Reason explains why an update is needed
+	Reason string
+}
+
+// KeySyncPlan is the set of changes needed to make a storage prefix match the source
+type KeySyncPlan struct {
+	Prefix    string
+	Changes   []*KeySyncChange
+	Unchanged []string
+}
+
+// KeySyncResult is the outcome of KeyStore.Sync, listing the paths that were changed
+type KeySyncResult struct {
+	Plan    *KeySyncPlan
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// Count returns the number of changes with the given action
+func (p *KeySyncPlan) Count(action KeySyncAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the plan for people to review.  Key contents are never printed.
+func (p *KeySyncPlan) String() string {
+	var b strings.Builder
+	writeSyncPlanSummary(&b, p.Prefix,
+		p.Count(KeySyncActionCreate), p.Count(KeySyncActionUpdate), p.Count(KeySyncActionDelete), len(p.Unchanged))
+
+	for _, change := range p.Changes {
+		switch change.Action {
+		case KeySyncActionCreate:
+			fmt.Fprintf(&b, "  + %s (%s)\n", change.Path, keyTypeOf(change.Key.ContentType))
+		case KeySyncActionUpdate:
+			fmt.Fprintf(&b, "  ~ %s: %s\n", change.Path, change.Reason)
+		case KeySyncActionDelete:
+			fmt.Fprintf(&b, "  - %s (%s)\n", change.Path, change.Current.KeyType)
+		}
+	}
+	return b.String()
+}
+
+// Sync mirrors the keys of a source into a storage prefix, like a plan and apply.  Keys missing
+// from storage are created and changed keys are updated.  When opts.Prune is set, keys below the
+// prefix that the source does not have are deleted.
+func (k *KeyStore) Sync(ctx context.Context, prefix string, source KeySource, opts *KeySyncOptions) (*KeySyncResult, error) {
+	if opts == nil {
+		opts = &KeySyncOptions{}
+	}
+
+	plan, err := k.planSync(ctx, keyRelativePath(prefix), source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, plan.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &KeySyncResult{Plan: plan}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, change := range plan.Changes {
+		dir, file := path.Split(path.Join(keyRelativePath(prefix), change.Path))
+		dir = strings.TrimSuffix(dir, "/")
+
+		var err error
+		switch change.Action {
+		case KeySyncActionCreate:
+			if _, err = k.CreateWithContext(ctx, dir, file, change.Key.ContentType, change.Key.Content); err == nil {
+				result.Created = append(result.Created, change.Path)
+			}
+		case KeySyncActionUpdate:
+			if _, err = k.UpdateWithContext(ctx, dir, file, change.Key.ContentType, change.Key.Content); err == nil {
+				result.Updated = append(result.Updated, change.Path)
+			}
+		case KeySyncActionDelete:
+			if err = k.DeleteWithContext(ctx, dir, file); err == nil {
+				result.Deleted = append(result.Deleted, change.Path)
+			}
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to %s %s: %w", change.Action, change.Path, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (k *KeyStore) planSync(ctx context.Context, prefix string, source KeySource, opts *KeySyncOptions) (*KeySyncPlan, error) {
+	keys, err := source.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(map[string]bool)
+	for _, key := range keys {
+		clean := path.Clean(key.Path)
+		if clean != key.Path || clean == "." || clean == ".." || strings.HasPrefix(clean, "/") || strings.HasPrefix(clean, "../") {
+			return nil, fmt.Errorf("invalid key path %q", key.Path)
+		}
+		switch key.ContentType {
+		case KeyContentTypePassword, KeyContentTypePublicKey, KeyContentTypePrivateKey:
+		default:
+			return nil, fmt.Errorf("key %s has unsupported content type %q", key.Path, key.ContentType)
+		}
+		if local[key.Path] {
+			return nil, fmt.Errorf("key %s is supplied more than once", key.Path)
+		}
+		local[key.Path] = true
+	}
+
+	stored := make(map[string]*KeyMetadata)
+	err = k.Walk(ctx, prefix, func(resource *KeyResource) error {
+		if resource.Type == "directory" {
+			return nil
+		}
+		meta := resource.Meta
+		rel := keyRelativePath(resource.Path)
+		if prefix != "" {
+			rel = strings.TrimPrefix(rel, prefix+"/")
+		}
+		stored[rel] = &meta
+		return nil
+	})
+	// a prefix that does not exist yet is simply empty
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	plan := &KeySyncPlan{Prefix: strings.TrimSuffix("keys/"+prefix, "/")}
+	for _, key := range keys {
+		current, ok := stored[key.Path]
+		if !ok {
+			plan.Changes = append(plan.Changes, &KeySyncChange{Action: KeySyncActionCreate, Path: key.Path, Key: key})
+			continue
+		}
+
+		reason, err := k.keyChanged(ctx, path.Join(prefix, key.Path), key, current, opts.Overwrite)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			plan.Unchanged = append(plan.Unchanged, key.Path)
+			continue
+		}
+		plan.Changes = append(plan.Changes, &KeySyncChange{
+			Action:  KeySyncActionUpdate,
+			Path:    key.Path,
+			Key:     key,
+			Current: current,
+			Reason:  reason,
+		})
+	}
+
+	if opts.Prune {
+		for rel, current := range stored {
+			if local[rel] {
+				continue
+			}
+			plan.Changes = append(plan.Changes, &KeySyncChange{Action: KeySyncActionDelete, Path: rel, Current: current})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(a, b int) bool {
+		ca, cb := plan.Changes[a], plan.Changes[b]
+		if ca.Action != cb.Action {
+			return syncActionRank(string(ca.Action)) < syncActionRank(string(cb.Action))
+		}
+		return ca.Path < cb.Path
+	})
+	sort.Strings(plan.Unchanged)
+
+	return plan, nil
+}
+
+// keyChanged explains why a stored key needs updating, or returns an empty string when it doesn't
+func (k *KeyStore) keyChanged(ctx context.Context, keyPath string, key *SourceKey, current *KeyMetadata, overwrite bool) (string, error) {
+	if keyTypeOf(key.ContentType) != current.KeyType {
+		return fmt.Sprintf("type %s -> %s", current.KeyType, keyTypeOf(key.ContentType)), nil
+	}
+	if int64(len(key.Content)) != current.ContentSize {
+		return fmt.Sprintf("size %d -> %d", current.ContentSize, len(key.Content)), nil
+	}
+
+	if key.ContentType == KeyContentTypePublicKey {
+		dir, file := path.Split(keyPath)
+		content, err := k.PublicKeyContentsWithContext(ctx, strings.TrimSuffix(dir, "/"), file)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(content, key.Content) {
+			return "content changed", nil
+		}
+		return "", nil
+	}
+
+	if overwrite {
+		return "overwrite", nil
+	}
+	return "", nil
+}
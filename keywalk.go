@@ -0,0 +1,258 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultKeyWalkConcurrency is the number of directories listed at once unless told otherwise
+const defaultKeyWalkConcurrency = 4
+
+// SkipKeyDir can be returned by a KeyWalkFunc for a directory to skip its contents
+var SkipKeyDir = errors.New("skip this directory")
+
+// KeyWalkFunc is called by Walk for every resource found below the root.  Calls are never made
+// concurrently, so the function needs no synchronization of its own.  Returning SkipKeyDir for a
+// directory skips its contents, any other error stops the walk and is returned by Walk.
+type KeyWalkFunc func(resource *KeyResource) error
+
+// Walk visits every key and directory below root, descending into each resource whose type is
+// directory.  Directories are listed concurrently, with at most 4 listings in flight at once.
+// The order resources are visited in is not defined.
+func (k *KeyStore) Walk(ctx context.Context, root string, fn KeyWalkFunc) error {
+	return k.walk(ctx, root, defaultKeyWalkConcurrency, fn)
+}
+
+func (k *KeyStore) walk(parent context.Context, root string, concurrency int, fn KeyWalkFunc) error {
+	if concurrency <= 0 {
+		concurrency = defaultKeyWalkConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	// visit calls fn with the lock held, and records the first error so the rest of the walk stops
+	visit := func(resource *KeyResource) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr != nil {
+			return false, firstErr
+		}
+		err := fn(resource)
+		if err == SkipKeyDir {
+			return false, nil
+		}
+		if err != nil {
+			firstErr = err
+			cancel()
+			return false, err
+		}
+		return true, nil
+	}
+
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		cancel()
+	}
+
+	var list func(dir string)
+	list = func(dir string) {
+		defer wg.Done()
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		keys, err := k.ListWithContext(ctx, dir)
+		<-sem
+		if err != nil {
+			if ctx.Err() == nil {
+				fail(fmt.Errorf("listing keys/%s: %w", dir, err))
+			}
+			return
+		}
+
+		for _, resource := range keys.Resources {
+			descend, err := visit(resource)
+			if err != nil {
+				return
+			}
+			if descend && resource.Type == "directory" {
+				wg.Add(1)
+				go list(keyRelativePath(resource.Path))
+			}
+		}
+	}
+
+	wg.Add(1)
+	list(keyRelativePath(root))
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return parent.Err()
+}
+
+// keyRelativePath strips the keys/ prefix and surrounding slashes from a storage path
+func keyRelativePath(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "keys" {
+		return ""
+	}
+	return strings.TrimPrefix(path, "keys/")
+}
+
+// KeyExportInput are the parameters for KeyStore.Export
+type KeyExportInput struct {
+	// Concurrency is the number of directories listed at once, 4 by default
+	Concurrency int
+}
+
+// KeyManifest lists every key below a storage path along with its metadata.  It never holds the
+// contents of a key, so it is safe to keep for audits or to compare between environments.
+type KeyManifest struct {
+	Root string              `json:"root"`
+	Keys []*KeyManifestEntry `json:"keys"`
+}
+
+// KeyManifestEntry is a single key of a manifest.  The path is relative to the manifest root.
+type KeyManifestEntry struct {
+	Path string      `json:"path"`
+	Meta KeyMetadata `json:"meta"`
+}
+
+// Export walks root and returns a manifest of every key below it, sorted by path
+func (k *KeyStore) Export(ctx context.Context, root string, input *KeyExportInput) (*KeyManifest, error) {
+	var concurrency int
+	if input != nil {
+		concurrency = input.Concurrency
+	}
+
+	root = keyRelativePath(root)
+	manifest := &KeyManifest{Root: "keys/" + root, Keys: []*KeyManifestEntry{}}
+	manifest.Root = strings.TrimSuffix(manifest.Root, "/")
+
+	err := k.walk(ctx, root, concurrency, func(resource *KeyResource) error {
+		if resource.Type == "directory" {
+			return nil
+		}
+		path := keyRelativePath(resource.Path)
+		if root != "" {
+			path = strings.TrimPrefix(path, root+"/")
+		}
+		manifest.Keys = append(manifest.Keys, &KeyManifestEntry{Path: path, Meta: resource.Meta})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(manifest.Keys, func(a, b int) bool {
+		return manifest.Keys[a].Path < manifest.Keys[b].Path
+	})
+	return manifest, nil
+}
+
+// KeyManifestChange is a key present in both manifests whose metadata differs
+type KeyManifestChange struct {
+	Path string
+	Old  KeyMetadata
+	New  KeyMetadata
+}
+
+// KeyManifestDiff is the difference between two key manifests
+type KeyManifestDiff struct {
+	Added   []*KeyManifestEntry
+	Removed []*KeyManifestEntry
+	Changed []*KeyManifestChange
+}
+
+// Empty reports whether the manifests hold the same keys with the same metadata
+func (d *KeyManifestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders the differences as a text report
+func (d *KeyManifestDiff) String() string {
+	var b strings.Builder
+	if d.Empty() {
+		b.WriteString("no changes\n")
+		return b.String()
+	}
+
+	for _, entry := range d.Added {
+		fmt.Fprintf(&b, "+ %s (%s)\n", entry.Path, entry.Meta.KeyType)
+	}
+	for _, entry := range d.Removed {
+		fmt.Fprintf(&b, "- %s (%s)\n", entry.Path, entry.Meta.KeyType)
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "~ %s: %s\n", change.Path, strings.Join(diffKeyMetadata(change.Old, change.New), ", "))
+	}
+	return b.String()
+}
+
+// DiffKeyManifests compares two manifests by path relative to their roots, so storage exported
+// from different roots or different servers can be compared
+func DiffKeyManifests(old, new *KeyManifest) *KeyManifestDiff {
+	before := make(map[string]*KeyManifestEntry, len(old.Keys))
+	for _, entry := range old.Keys {
+		before[entry.Path] = entry
+	}
+	after := make(map[string]*KeyManifestEntry, len(new.Keys))
+	for _, entry := range new.Keys {
+		after[entry.Path] = entry
+	}
+
+	diff := &KeyManifestDiff{}
+	for _, entry := range new.Keys {
+		previous, ok := before[entry.Path]
+		if !ok {
+			diff.Added = append(diff.Added, entry)
+			continue
+		}
+		if len(diffKeyMetadata(previous.Meta, entry.Meta)) > 0 {
+			diff.Changed = append(diff.Changed, &KeyManifestChange{Path: entry.Path, Old: previous.Meta, New: entry.Meta})
+		}
+	}
+	for _, entry := range old.Keys {
+		if _, ok := after[entry.Path]; !ok {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+	return diff
+}
+
+func diffKeyMetadata(a, b KeyMetadata) []string {
+	var changes []string
+	if a.KeyType != b.KeyType {
+		changes = append(changes, fmt.Sprintf("type %q -> %q", a.KeyType, b.KeyType))
+	}
+	if a.ContentType != b.ContentType {
+		changes = append(changes, fmt.Sprintf("content type %q -> %q", a.ContentType, b.ContentType))
+	}
+	if a.ContentSize != b.ContentSize {
+		changes = append(changes, fmt.Sprintf("size %d -> %d", a.ContentSize, b.ContentSize))
+	}
+	if a.ContentMask != b.ContentMask {
+		changes = append(changes, fmt.Sprintf("content mask %q -> %q", a.ContentMask, b.ContentMask))
+	}
+	return changes
+}
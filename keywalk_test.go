@@ -0,0 +1,114 @@
+package rundeck_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestKeyStorageWalk(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	keys := server.Client().KeyStorage()
+
+	server.PutKey("keys/app/db/password", "application/x-rundeck-data-password", []byte("hunter2"))
+	server.PutKey("keys/app/db/replica/password", "application/x-rundeck-data-password", []byte("hunter3"))
+	server.PutKey("keys/app/ssh/id_rsa", "application/octet-stream", []byte("private"))
+	server.PutKey("keys/app/ssh/id_rsa.pub", "application/pgp-keys", []byte("public"))
+	server.PutKey("keys/other/token", "application/x-rundeck-data-password", []byte("token"))
+
+	var visited []string
+	err := keys.Walk(ctx, "", func(resource *rundeck.KeyResource) error {
+		visited = append(visited, resource.Path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("failed to walk keys", err)
+	}
+	sort.Strings(visited)
+	expected := []string{
+		"keys/app", "keys/app/db", "keys/app/db/password", "keys/app/db/replica", "keys/app/db/replica/password",
+		"keys/app/ssh", "keys/app/ssh/id_rsa", "keys/app/ssh/id_rsa.pub", "keys/other", "keys/other/token",
+	}
+	if strings.Join(visited, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected resources visited: %v\n", visited)
+	}
+
+	visited = nil
+	err = keys.Walk(ctx, "keys/app", func(resource *rundeck.KeyResource) error {
+		if resource.Path == "keys/app/db" {
+			return rundeck.SkipKeyDir
+		}
+		visited = append(visited, resource.Path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("failed to walk keys", err)
+	}
+	sort.Strings(visited)
+	if strings.Join(visited, ",") != "keys/app/ssh,keys/app/ssh/id_rsa,keys/app/ssh/id_rsa.pub" {
+		t.Errorf("expected the db directory to be skipped: %v\n", visited)
+	}
+
+	stop := errors.New("stop")
+	if err := keys.Walk(ctx, "app", func(*rundeck.KeyResource) error { return stop }); err != stop {
+		t.Errorf("expected the walk function's error, received %v\n", err)
+	}
+
+	if err := keys.Walk(ctx, "missing", func(*rundeck.KeyResource) error { return nil }); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected walking a missing directory to be not found, received %v\n", err)
+	}
+}
+
+func TestKeyStorageExport(t *testing.T) {
+	prod := rundecktest.NewServer()
+	defer prod.Close()
+	staging := rundecktest.NewServer()
+	defer staging.Close()
+	ctx := context.Background()
+
+	prod.PutKey("keys/prod/db/password", "application/x-rundeck-data-password", []byte("hunter2"))
+	prod.PutKey("keys/prod/ssh/id_rsa", "application/octet-stream", []byte("private"))
+	prod.PutKey("keys/prod/ssh/id_rsa.pub", "application/pgp-keys", []byte("public"))
+
+	staging.PutKey("keys/staging/db/password", "application/x-rundeck-data-password", []byte("hunter"))
+	staging.PutKey("keys/staging/ssh/id_rsa", "application/octet-stream", []byte("private"))
+	staging.PutKey("keys/staging/api/token", "application/x-rundeck-data-password", []byte("token"))
+
+	before, err := prod.Client().KeyStorage().Export(ctx, "prod", &rundeck.KeyExportInput{Concurrency: 1})
+	if err != nil {
+		t.Fatal("failed to export keys", err)
+	}
+	if before.Root != "keys/prod" || len(before.Keys) != 3 || before.Keys[0].Path != "db/password" {
+		t.Errorf("unexpected manifest: %+v\n", before)
+	}
+	if before.Keys[0].Meta.KeyType != "password" || before.Keys[0].Meta.ContentSize != 7 {
+		t.Errorf("unexpected manifest metadata: %+v\n", before.Keys[0].Meta)
+	}
+
+	after, err := staging.Client().KeyStorage().Export(ctx, "keys/staging/", nil)
+	if err != nil {
+		t.Fatal("failed to export keys", err)
+	}
+
+	diff := rundeck.DiffKeyManifests(before, after)
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Changed) != 1 {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+	report := diff.String()
+	for _, line := range []string{"+ api/token (password)", "- ssh/id_rsa.pub (public)", "~ db/password: size 7 -> 6"} {
+		if !strings.Contains(report, line) {
+			t.Errorf("expected %q in diff:\n%s", line, report)
+		}
+	}
+
+	if !rundeck.DiffKeyManifests(before, before).Empty() {
+		t.Error("expected a manifest to have no differences with itself")
+	}
+}
@@ -1,7 +1,9 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
+	"net/url"
 )
 
 // LogStore contains information about logstorage in the system API
@@ -16,9 +18,14 @@ func (c *Client) LogStore() *LogStore {
 
 // LogStorage returns log storage information and stats
 func (l *LogStore) LogStorage() (*LogStorageStats, error) {
+	return l.LogStorageWithContext(context.Background())
+}
+
+// LogStorageWithContext is the same as LogStorage with the addition of the ability to pass a context.
+func (l *LogStore) LogStorageWithContext(ctx context.Context) (*LogStorageStats, error) {
 	url := l.c.RundeckAddr + "/system/logstorage"
 
-	res, err := l.c.checkResponseOK(l.c.get(url))
+	res, err := l.c.checkResponseOK(l.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -30,9 +37,25 @@ func (l *LogStore) LogStorage() (*LogStorageStats, error) {
 
 // IncompleteLogStorage lists executions with incomplete logstorage
 func (l *LogStore) IncompleteLogStorage() (*IncompleteLogStorageResponse, error) {
-	url := l.c.RundeckAddr + "/system/logstorage/incomplete"
+	return l.IncompleteLogStorageWithContext(context.Background())
+}
+
+// IncompleteLogStorageWithContext is the same as IncompleteLogStorage with the addition of the ability to pass a context.
+func (l *LogStore) IncompleteLogStorageWithContext(ctx context.Context) (*IncompleteLogStorageResponse, error) {
+	return l.incompleteLogStorage(ctx, nil)
+}
+
+func (l *LogStore) incompleteLogStorage(ctx context.Context, paging *PagingInfo) (*IncompleteLogStorageResponse, error) {
+	uri, err := url.Parse(l.c.RundeckAddr + "/system/logstorage/incomplete")
+	if err != nil {
+		return nil, err
+	}
 
-	res, err := l.c.checkResponseOK(l.c.get(url))
+	query := uri.Query()
+	encodePagingInfo(query, paging)
+	uri.RawQuery = query.Encode()
+
+	res, err := l.c.checkResponseOK(l.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -44,9 +67,14 @@ func (l *LogStore) IncompleteLogStorage() (*IncompleteLogStorageResponse, error)
 
 // ResumeIncompleteLogStorage resumes processing incomplete log storage uploads
 func (l *LogStore) ResumeIncompleteLogStorage() (*ResumedIncompleteLogStorageResponse, error) {
+	return l.ResumeIncompleteLogStorageWithContext(context.Background())
+}
+
+// ResumeIncompleteLogStorageWithContext is the same as ResumeIncompleteLogStorage with the addition of the ability to pass a context.
+func (l *LogStore) ResumeIncompleteLogStorageWithContext(ctx context.Context) (*ResumedIncompleteLogStorageResponse, error) {
 	url := l.c.RundeckAddr + "/system/logstorage/incomplete/resume"
 
-	res, err := l.c.checkResponseOK(l.c.post(url, nil))
+	res, err := l.c.checkResponseOK(l.c.post(ctx, url, nil))
 	if err != nil {
 		return nil, err
 	}
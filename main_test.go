@@ -0,0 +1,24 @@
+package rundeck_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+// TestMain runs the suite against the in-memory fake unless RUNDECK_TOKEN points the tests at a live instance
+func TestMain(m *testing.M) {
+	if os.Getenv(rundeck.EnvRundeckToken) != "" {
+		os.Exit(m.Run())
+	}
+
+	server := rundecktest.NewServer()
+	os.Setenv(rundeck.EnvRundeckServerURL, server.URL)
+	os.Setenv(rundeck.EnvRundeckToken, rundecktest.DefaultToken)
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
@@ -1,5 +1,14 @@
 package rundeck
 
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of items iterators request per page unless told otherwise
+const defaultPageSize = 100
+
 // PagingInfo contains information relating to a paginated response
 type PagingInfo struct {
 	Count  int `json:"count"`
@@ -7,3 +16,211 @@ type PagingInfo struct {
 	Max    int `json:"max"`
 	Offset int `json:"offset"`
 }
+
+func encodePagingInfo(query url.Values, paging *PagingInfo) {
+	if paging == nil {
+		return
+	}
+
+	if paging.Max != 0 {
+		query.Add("max", strconv.Itoa(paging.Max))
+	}
+
+	if paging.Offset != 0 {
+		query.Add("offset", strconv.Itoa(paging.Offset))
+	}
+}
+
+// pageFetcher retrieves the page at the given offset, returning the number of items it held
+type pageFetcher func(ctx context.Context, paging PagingInfo) (int, PagingInfo, error)
+
+// pager tracks the position of an iterator across lazily fetched pages
+type pager struct {
+	ctx     context.Context
+	fetch   pageFetcher
+	max     int
+	offset  int
+	total   int
+	size    int
+	index   int
+	fetched bool
+	done    bool
+	err     error
+}
+
+func newPager(ctx context.Context, paging *PagingInfo, fetch pageFetcher) *pager {
+	p := &pager{
+		ctx:   ctx,
+		fetch: fetch,
+		max:   defaultPageSize,
+		index: -1,
+	}
+	if paging != nil {
+		if paging.Max > 0 {
+			p.max = paging.Max
+		}
+		p.offset = paging.Offset
+	}
+	return p
+}
+
+func (p *pager) next() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	p.index++
+	if p.index < p.size {
+		return true
+	}
+
+	if p.fetched && p.offset >= p.total {
+		p.done = true
+		return false
+	}
+
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	size, info, err := p.fetch(p.ctx, PagingInfo{Max: p.max, Offset: p.offset})
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.fetched = true
+	p.size = size
+	p.index = 0
+	p.offset += size
+	p.total = info.Total
+
+	if size == 0 {
+		p.done = true
+		return false
+	}
+	return true
+}
+
+// ExecutionIterator lazily pages through executions.  Stop calling Next to end early.
+//
+//	it := cli.Executions().QueryAll(ctx, "project", nil)
+//	for it.Next() {
+//		execution := it.Execution()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ExecutionIterator struct {
+	*pager
+	page []*Execution
+}
+
+func newExecutionIterator(ctx context.Context, paging *PagingInfo, fetch func(ctx context.Context, paging *PagingInfo) ([]*Execution, PagingInfo, error)) *ExecutionIterator {
+	it := &ExecutionIterator{}
+	it.pager = newPager(ctx, paging, func(ctx context.Context, paging PagingInfo) (int, PagingInfo, error) {
+		page, info, err := fetch(ctx, &paging)
+		it.page = page
+		return len(page), info, err
+	})
+	return it
+}
+
+// Next advances to the next execution, fetching the next page when needed.  It returns false
+// once every execution has been returned or an error occurs.
+func (it *ExecutionIterator) Next() bool {
+	return it.next()
+}
+
+// Execution returns the current execution
+func (it *ExecutionIterator) Execution() *Execution {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ExecutionIterator) Err() error {
+	return it.err
+}
+
+// FileOptionIterator lazily pages through uploaded files.  Stop calling Next to end early.
+type FileOptionIterator struct {
+	*pager
+	page []*FileOption
+}
+
+// Next advances to the next file, fetching the next page when needed.  It returns false
+// once every file has been returned or an error occurs.
+func (it *FileOptionIterator) Next() bool {
+	return it.next()
+}
+
+// FileOption returns the current file
+func (it *FileOptionIterator) FileOption() *FileOption {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *FileOptionIterator) Err() error {
+	return it.err
+}
+
+// QueryAll iterates over every execution matching the query.  input.Max sets the page size and
+// input.Offset the starting point.
+func (e *Executions) QueryAll(ctx context.Context, project string, input *ExecutionQueryInput) *ExecutionIterator {
+	var query ExecutionQueryInput
+	if input != nil {
+		query = *input
+	}
+
+	return newExecutionIterator(ctx, &query.PagingInfo, func(ctx context.Context, paging *PagingInfo) ([]*Execution, PagingInfo, error) {
+		query.PagingInfo = *paging
+		res, err := e.QueryWithContext(ctx, project, &query)
+		if err != nil {
+			return nil, PagingInfo{}, err
+		}
+		return res.Executions, res.PagingInfo, nil
+	})
+}
+
+// GetExecutionsForAJobAll iterates over every execution of the job.  paging sets the page size and starting offset.
+func (e *Executions) GetExecutionsForAJobAll(ctx context.Context, id string, status *string, paging *PagingInfo) *ExecutionIterator {
+	return newExecutionIterator(ctx, paging, func(ctx context.Context, paging *PagingInfo) ([]*Execution, PagingInfo, error) {
+		res, err := e.executionsForJob(ctx, id, status, paging)
+		if err != nil {
+			return nil, PagingInfo{}, err
+		}
+		return res.Executions, res.PagingInfo, nil
+	})
+}
+
+// IncompleteLogStorageAll iterates over every execution with incomplete log storage.  paging sets the page size and starting offset.
+func (l *LogStore) IncompleteLogStorageAll(ctx context.Context, paging *PagingInfo) *ExecutionIterator {
+	return newExecutionIterator(ctx, paging, func(ctx context.Context, paging *PagingInfo) ([]*Execution, PagingInfo, error) {
+		res, err := l.incompleteLogStorage(ctx, paging)
+		if err != nil {
+			return nil, PagingInfo{}, err
+		}
+		return res.Executions, res.PagingInfo, nil
+	})
+}
+
+// ListFilesUploadedForJobAll iterates over every file uploaded for the job.  paging sets the page size and starting offset.
+func (j *Jobs) ListFilesUploadedForJobAll(ctx context.Context, id string, fileState *FileState, paging *PagingInfo) *FileOptionIterator {
+	it := &FileOptionIterator{}
+	it.pager = newPager(ctx, paging, func(ctx context.Context, paging PagingInfo) (int, PagingInfo, error) {
+		res, err := j.listFilesUploadedForJob(ctx, id, fileState, &paging)
+		if err != nil {
+			return 0, PagingInfo{}, err
+		}
+		it.page = res.File
+		return len(res.File), res.PagingInfo, nil
+	})
+	return it
+}
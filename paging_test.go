@@ -0,0 +1,157 @@
+package rundeck_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestQueryAllPaginates(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Test"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	numExecutions := 5
+	for i := 0; i < numExecutions; i++ {
+		_, err := cli.Adhoc().RunCommandString(&rundeck.AdhocCommandStringInput{
+			Exec:         "pwd",
+			AdhocOptions: rundeck.AdhocOptions{Project: "Test"},
+		})
+		if err != nil {
+			t.Fatal("failed to run adhoc command", err)
+		}
+	}
+
+	input := &rundeck.ExecutionQueryInput{PagingInfo: rundeck.PagingInfo{Max: 2}}
+
+	seen := make(map[int]bool)
+	it := cli.Executions().QueryAll(context.Background(), "Test", input)
+	for it.Next() {
+		seen[it.Execution().ID] = true
+	}
+	if err := it.Err(); err != nil {
+		t.Error("iteration failed", err)
+	}
+	if len(seen) != numExecutions {
+		t.Errorf("unexpected number of executions.  expected: %d\tactual: %d\n", numExecutions, len(seen))
+	}
+
+	count := 0
+	it = cli.Executions().QueryAll(context.Background(), "Test", input)
+	for it.Next() {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 || it.Err() != nil {
+		t.Errorf("early termination failed.  count: %d\terr: %v\n", count, it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = cli.Executions().QueryAll(ctx, "Test", input)
+	if it.Next() || it.Err() != context.Canceled {
+		t.Errorf("canceled context should stop iteration: %v\n", it.Err())
+	}
+}
+
+func TestJobPagingIterators(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	// count the pages requested from each endpoint
+	pages := make(map[string]int)
+	config := server.Config()
+	config.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("max") == "2" {
+			pages[req.URL.Path[strings.Index(req.URL.Path, "/api/"):]]++
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	cli := rundeck.NewClient(config)
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Test"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	const jobID = "6f1c2b3a-4d5e-4f60-8a7b-9c0d1e2f3a4b"
+	def, _ := rundeck.NewJob("paged").UUID(jobID).Step(rundeck.Command("pwd")).Build()
+	if _, err := cli.Jobs().Sync(ctx, "Test", []*rundeck.JobDefinition{def}, nil); err != nil {
+		t.Fatal("failed to create job", err)
+	}
+
+	const total = 5
+	var ids []int
+	for i := 0; i < total; i++ {
+		execution, err := cli.Jobs().Run(jobID, nil)
+		if err != nil {
+			t.Fatal("failed to run job", err)
+		}
+		ids = append(ids, execution.ID)
+		if _, err := cli.Jobs().UploadFileForJobOption(jobID, "file", []byte("content"), nil); err != nil {
+			t.Fatal("failed to upload file", err)
+		}
+	}
+	server.SetIncompleteLogStorage(ids...)
+
+	iterators := map[string]func(paging *rundeck.PagingInfo) (next func() bool, err func() error){
+		"/api/24/job/" + jobID + "/executions": func(paging *rundeck.PagingInfo) (func() bool, func() error) {
+			it := cli.Executions().GetExecutionsForAJobAll(ctx, jobID, nil, paging)
+			return it.Next, it.Err
+		},
+		"/api/24/system/logstorage/incomplete": func(paging *rundeck.PagingInfo) (func() bool, func() error) {
+			it := cli.LogStore().IncompleteLogStorageAll(ctx, paging)
+			return it.Next, it.Err
+		},
+		"/api/24/job/" + jobID + "/input/files": func(paging *rundeck.PagingInfo) (func() bool, func() error) {
+			it := cli.Jobs().ListFilesUploadedForJobAll(ctx, jobID, nil, paging)
+			return it.Next, it.Err
+		},
+	}
+
+	for path, iterate := range iterators {
+		next, err := iterate(&rundeck.PagingInfo{Max: 2})
+		count := 0
+		for next() {
+			count++
+		}
+		if err() != nil || count != total {
+			t.Errorf("%s: expected %d items, received %d: %v\n", path, total, count, err())
+		}
+		if pages[path] != 3 {
+			t.Errorf("%s: expected 3 pages of 2, received %d\n", path, pages[path])
+		}
+
+		next, err = iterate(&rundeck.PagingInfo{Max: 2, Offset: 1})
+		count = 0
+		for next() {
+			count++
+		}
+		if err() != nil || count != total-1 {
+			t.Errorf("%s: expected %d items after the offset, received %d: %v\n", path, total-1, count, err())
+		}
+
+		// stopping early leaves the remaining pages unrequested
+		pages[path] = 0
+		next, err = iterate(&rundeck.PagingInfo{Max: 2})
+		count = 0
+		for next() {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if count != 3 || err() != nil || pages[path] != 2 {
+			t.Errorf("%s: early termination failed.  count: %d\tpages: %d\terr: %v\n", path, count, pages[path], err())
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// ProjectACL manages the ACL policies stored in a project.  Project policies have no context section,
+// they apply to the project they are stored in.
+type ProjectACL struct {
+	c *Client
+}
+
+// ProjectACL interacts with the project ACL API
+func (c *Client) ProjectACL() *ProjectACL {
+	return &ProjectACL{c: c}
+}
+
+// List returns an overview of the ACLs stored in a project
+func (p *ProjectACL) List(project string) (*ListACLsResponse, error) {
+	return p.ListWithContext(context.Background(), project)
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (p *ProjectACL) ListWithContext(ctx context.Context, project string) (*ListACLsResponse, error) {
+	return p.c.listACLs(ctx, p.c.aclURL(ProjectACLScope(project), ""))
+}
+
+// Get retrieves the YAML text of a project ACL policy file
+func (p *ProjectACL) Get(project, name string) ([]byte, error) {
+	return p.GetWithContext(context.Background(), project, name)
+}
+
+// GetWithContext is the same as Get with the addition of the ability to pass a context.
+func (p *ProjectACL) GetWithContext(ctx context.Context, project, name string) ([]byte, error) {
+	return p.c.getACL(ctx, p.c.aclURL(ProjectACLScope(project), name))
+}
+
+// GetPolicies retrieves a project ACL policy file and parses its documents
+func (p *ProjectACL) GetPolicies(project, name string) ([]*ACLPolicy, error) {
+	return p.GetPoliciesWithContext(context.Background(), project, name)
+}
+
+// GetPoliciesWithContext is the same as GetPolicies with the addition of the ability to pass a context.
+func (p *ProjectACL) GetPoliciesWithContext(ctx context.Context, project, name string) ([]*ACLPolicy, error) {
+	content, err := p.GetWithContext(ctx, project, name)
+	if err != nil {
+		return nil, err
+	}
+	return ParseACLPolicies(content)
+}
+
+// Create is used to create a project ACL policy.  When Rundeck rejects the policy as invalid the error is an *ACLValidationError.
+func (p *ProjectACL) Create(project, name string, policy []byte) error {
+	return p.CreateWithContext(context.Background(), project, name, policy)
+}
+
+// CreateWithContext is the same as Create with the addition of the ability to pass a context.
+func (p *ProjectACL) CreateWithContext(ctx context.Context, project, name string, policy []byte) error {
+	return p.c.createACL(ctx, p.c.aclURL(ProjectACLScope(project), name), policy)
+}
+
+// Update updates an existing project ACL policy.  When Rundeck rejects the policy as invalid the error is an *ACLValidationError.
+func (p *ProjectACL) Update(project, name string, policy []byte) error {
+	return p.UpdateWithContext(context.Background(), project, name, policy)
+}
+
+// UpdateWithContext is the same as Update with the addition of the ability to pass a context.
+func (p *ProjectACL) UpdateWithContext(ctx context.Context, project, name string, policy []byte) error {
+	return p.c.updateACL(ctx, p.c.aclURL(ProjectACLScope(project), name), policy)
+}
+
+// Delete removes a project ACL policy file
+func (p *ProjectACL) Delete(project, name string) error {
+	return p.DeleteWithContext(context.Background(), project, name)
+}
+
+// DeleteWithContext is the same as Delete with the addition of the ability to pass a context.
+func (p *ProjectACL) DeleteWithContext(ctx context.Context, project, name string) error {
+	return p.c.deleteACL(ctx, p.c.aclURL(ProjectACLScope(project), name))
+}
+
+// Copy creates a copy of a policy in another scope, under the same name.  Copying a system policy into a
+// project removes its context, which must be a project context matching that project.  Copying a project
+// policy to the system scope adds a context matching only its project.  Since those copies are rewritten
+// from the parsed policy, comments are not kept, and a policy with keys the model doesn't know is refused
+// rather than copied without them.
+func (a *ACL) Copy(ctx context.Context, name string, from, to ACLScope) error {
+	if from == to {
+		return fmt.Errorf("cannot copy %s within the %s scope", sanitizeACLName(name), from)
+	}
+
+	content, err := a.c.getACL(ctx, a.c.aclURL(from, name))
+	if err != nil {
+		return err
+	}
+	content, err = convertACLPolicyScope(content, from, to)
+	if err != nil {
+		return fmt.Errorf("%s: %v", sanitizeACLName(name), err)
+	}
+	return a.c.createACL(ctx, a.c.aclURL(to, name), content)
+}
+
+// Move copies a policy to another scope, then deletes the original
+func (a *ACL) Move(ctx context.Context, name string, from, to ACLScope) error {
+	if err := a.Copy(ctx, name, from, to); err != nil {
+		return err
+	}
+	return a.c.deleteACL(ctx, a.c.aclURL(from, name))
+}
+
+// convertACLPolicyScope rewrites the context of a policy so it means the same thing in another scope
+func convertACLPolicyScope(content []byte, from, to ACLScope) ([]byte, error) {
+	if (from.Project == "") == (to.Project == "") {
+		// project policies don't mention their project, so they move between projects as they are
+		return content, nil
+	}
+
+	policies, err := ParseACLPolicies(content)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, policy := range policies {
+		if to.Project == "" {
+			policy.Context = ACLContext{Project: regexp.QuoteMeta(from.Project)}
+			continue
+		}
+
+		if policy.Context.Project == "" {
+			return nil, fmt.Errorf("policy %d has no project context and cannot be stored in a project", i+1)
+		}
+		re, err := regexp.Compile("^(?:" + policy.Context.Project + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("policy %d: %v", i+1, err)
+		}
+		if !re.MatchString(to.Project) {
+			return nil, fmt.Errorf("policy %d does not apply to project %s", i+1, to.Project)
+		}
+		policy.Context = ACLContext{}
+	}
+
+	return MarshalACLPolicies(policies)
+}
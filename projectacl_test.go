@@ -0,0 +1,185 @@
+package rundeck_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const testProjectACLPolicy = `description: Ops can run deploy jobs
+for:
+  job:
+    - match:
+        group: 'deploy/.*'
+      allow: [run, read]
+by:
+  group: ops
+`
+
+func TestProjectACL(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+	acls := cli.ProjectACL()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "ProjectACL"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("ProjectACL")
+
+	if err := acls.Create("ProjectACL", "deploy", []byte(testProjectACLPolicy)); err != nil {
+		t.Fatal("failed to create project policy", err)
+	}
+
+	list, err := acls.List("ProjectACL")
+	if err != nil {
+		t.Fatal("failed to list project policies", err)
+	}
+	if len(list.Resources) != 1 || list.Resources[0].Name != "deploy.aclpolicy" {
+		t.Errorf("unexpected project policies: %+v\n", list.Resources)
+	}
+
+	content, err := acls.Get("ProjectACL", "deploy.aclpolicy")
+	if err != nil {
+		t.Fatal("failed to get project policy", err)
+	}
+	if string(content) != testProjectACLPolicy {
+		t.Errorf("unexpected project policy:\n%s", content)
+	}
+
+	updated := strings.Replace(testProjectACLPolicy, "[run, read]", "[read]", 1)
+	if err := acls.Update("ProjectACL", "deploy", []byte(updated)); err != nil {
+		t.Fatal("failed to update project policy", err)
+	}
+	policies, err := acls.GetPolicies("ProjectACL", "deploy")
+	if err != nil {
+		t.Fatal("failed to get project policies", err)
+	}
+	if len(policies) != 1 || len(policies[0].For.Job[0].Allow) != 1 {
+		t.Errorf("unexpected project policies: %+v\n", policies)
+	}
+
+	// project policies can't name a context
+	var validation *rundeck.ACLValidationError
+	if err := acls.Create("ProjectACL", "system", []byte(testACLPolicy)); !errors.As(err, &validation) {
+		t.Errorf("expected a validation error, received %v\n", err)
+	}
+
+	if _, err := cli.ACL().Get("deploy"); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected project policies to be separate from system policies, received %v\n", err)
+	}
+
+	if err := acls.Delete("ProjectACL", "deploy"); err != nil {
+		t.Fatal("failed to delete project policy", err)
+	}
+	if _, err := acls.Get("ProjectACL", "deploy"); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected the project policy to be deleted, received %v\n", err)
+	}
+	if _, err := acls.List("MissingProject"); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected a missing project to be not found, received %v\n", err)
+	}
+}
+
+func TestACLMoveBetweenScopes(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+	ctx := context.Background()
+	project := rundeck.ProjectACLScope("ACLScopes")
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "ACLScopes"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("ACLScopes")
+
+	if err := cli.ProjectACL().Create("ACLScopes", "deploy", []byte(testProjectACLPolicy)); err != nil {
+		t.Fatal("failed to create project policy", err)
+	}
+
+	if err := cli.ACL().Copy(ctx, "deploy", project, rundeck.SystemACLScope); err != nil {
+		t.Fatal("failed to copy policy to the system scope", err)
+	}
+	defer cli.ACL().Delete("deploy")
+
+	policies, err := cli.ACL().GetPolicies("deploy")
+	if err != nil {
+		t.Fatal("failed to get system policy", err)
+	}
+	if len(policies) != 1 || policies[0].Context.Project != "ACLScopes" {
+		t.Errorf("expected the copy to be limited to its project: %+v\n", policies)
+	}
+	if _, err := cli.ProjectACL().Get("ACLScopes", "deploy"); err != nil {
+		t.Error("expected the copied policy to remain in the project", err)
+	}
+
+	if err := cli.ACL().Copy(ctx, "deploy", project, rundeck.SystemACLScope); !errors.Is(err, rundeck.ErrConflict) {
+		t.Errorf("expected copying over an existing policy to conflict, received %v\n", err)
+	}
+
+	if err := cli.ProjectACL().Delete("ACLScopes", "deploy"); err != nil {
+		t.Fatal("failed to delete project policy", err)
+	}
+	if err := cli.ACL().Move(ctx, "deploy", rundeck.SystemACLScope, project); err != nil {
+		t.Fatal("failed to move policy into the project", err)
+	}
+	if _, err := cli.ACL().Get("deploy"); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected the moved policy to be deleted, received %v\n", err)
+	}
+	policies, err = cli.ProjectACL().GetPolicies("ACLScopes", "deploy")
+	if err != nil {
+		t.Fatal("failed to get project policy", err)
+	}
+	if policies[0].Context != (rundeck.ACLContext{}) {
+		t.Errorf("expected the context to be removed: %+v\n", policies[0].Context)
+	}
+
+	// a policy for other projects has no meaning inside this one
+	if err := cli.ACL().Create("others", []byte(testACLPolicy)); err != nil {
+		t.Fatal("failed to create policy", err)
+	}
+	defer cli.ACL().Delete("others")
+	if err := cli.ACL().Copy(ctx, "others", rundeck.SystemACLScope, project); err == nil || !strings.Contains(err.Error(), "does not apply to project ACLScopes") {
+		t.Errorf("expected the copy to be refused, received %v\n", err)
+	}
+}
+
+func TestACLMoveKeepsSelectors(t *testing.T) {
+	cli := rundeck.NewClient(nil)
+	ctx := context.Background()
+	project := rundeck.ProjectACLScope("ACLSelectors")
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "ACLSelectors"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+	defer cli.Projects().Delete("ACLSelectors")
+
+	const policy = `for:
+  job:
+    - subset:
+        tags: [prod]
+      allow: run
+by:
+  group: ops
+notBy:
+  username: mallory
+`
+	if err := cli.ProjectACL().Create("ACLSelectors", "selectors", []byte(policy)); err != nil {
+		t.Fatal("failed to create project policy", err)
+	}
+	if err := cli.ACL().Move(ctx, "selectors", project, rundeck.SystemACLScope); err != nil {
+		t.Fatal("failed to move policy to the system scope", err)
+	}
+	defer cli.ACL().Delete("selectors")
+
+	policies, err := cli.ACL().GetPolicies("selectors")
+	if err != nil {
+		t.Fatal("failed to get system policy", err)
+	}
+	moved := policies[0]
+	if moved.NotBy == nil || moved.NotBy.Username[0] != "mallory" {
+		t.Errorf("expected notBy to survive the move: %+v\n", moved)
+	}
+	if len(moved.For.Job) != 1 || moved.For.Job[0].Subset["tags"][0] != "prod" {
+		t.Errorf("expected subset to survive the move: %+v\n", moved.For.Job)
+	}
+
+}
@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -109,9 +110,14 @@ func (c *Client) Projects() *Projects {
 
 // List returns a list of the projects
 func (p *Projects) List() ([]*Project, error) {
+	return p.ListWithContext(context.Background())
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (p *Projects) ListWithContext(ctx context.Context) ([]*Project, error) {
 	rawURL := p.c.RundeckAddr + "/projects"
 
-	res, err := p.c.checkResponseOK(p.c.get(rawURL))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +129,11 @@ func (p *Projects) List() ([]*Project, error) {
 
 // Create will make a new project
 func (p *Projects) Create(data *CreateProjectInput) (*ProjectInfo, error) {
+	return p.CreateWithContext(context.Background(), data)
+}
+
+// CreateWithContext is the same as Create with the addition of the ability to pass a context.
+func (p *Projects) CreateWithContext(ctx context.Context, data *CreateProjectInput) (*ProjectInfo, error) {
 	if data == nil {
 		return nil, errors.New("data cannot be nil")
 	}
@@ -134,7 +145,7 @@ func (p *Projects) Create(data *CreateProjectInput) (*ProjectInfo, error) {
 		return nil, err
 	}
 
-	res, err := p.c.checkResponseCreated(p.c.post(rawURL, bytes.NewReader(bs)))
+	res, err := p.c.checkResponseCreated(p.c.post(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -146,9 +157,14 @@ func (p *Projects) Create(data *CreateProjectInput) (*ProjectInfo, error) {
 
 // GetInfo returns project info
 func (p *Projects) GetInfo(project string) (*ProjectInfo, error) {
+	return p.GetInfoWithContext(context.Background(), project)
+}
+
+// GetInfoWithContext is the same as GetInfo with the addition of the ability to pass a context.
+func (p *Projects) GetInfoWithContext(ctx context.Context, project string) (*ProjectInfo, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project
 
-	res, err := p.c.checkResponseOK(p.c.get(rawURL))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -160,9 +176,14 @@ func (p *Projects) GetInfo(project string) (*ProjectInfo, error) {
 
 // Delete removes an existing project
 func (p *Projects) Delete(project string) error {
+	return p.DeleteWithContext(context.Background(), project)
+}
+
+// DeleteWithContext is the same as Delete with the addition of the ability to pass a context.
+func (p *Projects) DeleteWithContext(ctx context.Context, project string) error {
 	rawURL := p.c.RundeckAddr + "/project/" + project
 
-	res, err := p.c.checkResponseNoContent(p.c.delete(rawURL, nil))
+	res, err := p.c.checkResponseNoContent(p.c.delete(ctx, rawURL, nil))
 	if err != nil {
 		return err
 	}
@@ -173,9 +194,14 @@ func (p *Projects) Delete(project string) error {
 
 // Configuration retrieves the project configuration data
 func (p *Projects) Configuration(project string) (map[string]string, error) {
+	return p.ConfigurationWithContext(context.Background(), project)
+}
+
+// ConfigurationWithContext is the same as Configuration with the addition of the ability to pass a context.
+func (p *Projects) ConfigurationWithContext(ctx context.Context, project string) (map[string]string, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/config"
 
-	res, err := p.c.checkResponseOK(p.c.get(rawURL))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -187,6 +213,11 @@ func (p *Projects) Configuration(project string) (map[string]string, error) {
 
 // Configure modifies the project configuration data
 func (p *Projects) Configure(project string, config map[string]string) (map[string]string, error) {
+	return p.ConfigureWithContext(context.Background(), project, config)
+}
+
+// ConfigureWithContext is the same as Configure with the addition of the ability to pass a context.
+func (p *Projects) ConfigureWithContext(ctx context.Context, project string, config map[string]string) (map[string]string, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/config"
 
 	bs, err := json.Marshal(config)
@@ -194,7 +225,7 @@ func (p *Projects) Configure(project string, config map[string]string) (map[stri
 		return nil, err
 	}
 
-	res, err := p.c.checkResponseOK(p.c.put(rawURL, bytes.NewReader(bs)))
+	res, err := p.c.checkResponseOK(p.c.put(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -206,9 +237,14 @@ func (p *Projects) Configure(project string, config map[string]string) (map[stri
 
 // GetConfigKey retieves the value
 func (p *Projects) GetConfigKey(project, key string) (*ProjectConfigKeyPair, error) {
+	return p.GetConfigKeyWithContext(context.Background(), project, key)
+}
+
+// GetConfigKeyWithContext is the same as GetConfigKey with the addition of the ability to pass a context.
+func (p *Projects) GetConfigKeyWithContext(ctx context.Context, project, key string) (*ProjectConfigKeyPair, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/config/" + key
 
-	res, err := p.c.checkResponseOK(p.c.get(rawURL))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -220,6 +256,11 @@ func (p *Projects) GetConfigKey(project, key string) (*ProjectConfigKeyPair, err
 
 // SetConfigKey modifies the value
 func (p *Projects) SetConfigKey(project string, keyPair *ProjectConfigKeyPair) (*ProjectConfigKeyPair, error) {
+	return p.SetConfigKeyWithContext(context.Background(), project, keyPair)
+}
+
+// SetConfigKeyWithContext is the same as SetConfigKey with the addition of the ability to pass a context.
+func (p *Projects) SetConfigKeyWithContext(ctx context.Context, project string, keyPair *ProjectConfigKeyPair) (*ProjectConfigKeyPair, error) {
 	if keyPair == nil {
 		return nil, errors.New("keyPair cannot be nil when setting a config key")
 	}
@@ -230,7 +271,7 @@ func (p *Projects) SetConfigKey(project string, keyPair *ProjectConfigKeyPair) (
 	}
 
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/config/" + keyPair.Key
-	res, err := p.c.checkResponseOK(p.c.put(rawURL, bytes.NewReader(bs)))
+	res, err := p.c.checkResponseOK(p.c.put(ctx, rawURL, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -242,13 +283,23 @@ func (p *Projects) SetConfigKey(project string, keyPair *ProjectConfigKeyPair) (
 
 // DeleteConfigKey removes the key
 func (p *Projects) DeleteConfigKey(project, key string) error {
+	return p.DeleteConfigKeyWithContext(context.Background(), project, key)
+}
+
+// DeleteConfigKeyWithContext is the same as DeleteConfigKey with the addition of the ability to pass a context.
+func (p *Projects) DeleteConfigKeyWithContext(ctx context.Context, project, key string) error {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/config/" + key
-	_, err := p.c.checkResponseNoContent(p.c.delete(rawURL, nil))
+	_, err := p.c.checkResponseNoContent(p.c.delete(ctx, rawURL, nil))
 	return err
 }
 
 // ArchiveExport exports a zip archive of the project synchronously
 func (p *Projects) ArchiveExport(project string, input *ArchiveExportInput) ([]byte, error) {
+	return p.ArchiveExportWithContext(context.Background(), project, input)
+}
+
+// ArchiveExportWithContext is the same as ArchiveExport with the addition of the ability to pass a context.
+func (p *Projects) ArchiveExportWithContext(ctx context.Context, project string, input *ArchiveExportInput) ([]byte, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/export"
 
 	uri, err := url.Parse(rawURL)
@@ -257,7 +308,7 @@ func (p *Projects) ArchiveExport(project string, input *ArchiveExportInput) ([]b
 	}
 	uri.RawQuery = p.encodeArchiveExportInput(uri.Query(), input)
 
-	res, err := p.c.checkResponseOK(p.c.get(uri.String()))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -268,6 +319,11 @@ func (p *Projects) ArchiveExport(project string, input *ArchiveExportInput) ([]b
 
 // ArchiveExportAsync exports a zip archive of the project asynchronously
 func (p *Projects) ArchiveExportAsync(project string, input *ArchiveExportInput) (*ArchiveExportAsyncStatusResponse, error) {
+	return p.ArchiveExportAsyncWithContext(context.Background(), project, input)
+}
+
+// ArchiveExportAsyncWithContext is the same as ArchiveExportAsync with the addition of the ability to pass a context.
+func (p *Projects) ArchiveExportAsyncWithContext(ctx context.Context, project string, input *ArchiveExportInput) (*ArchiveExportAsyncStatusResponse, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/export/async"
 
 	uri, err := url.Parse(rawURL)
@@ -276,7 +332,7 @@ func (p *Projects) ArchiveExportAsync(project string, input *ArchiveExportInput)
 	}
 	uri.RawQuery = p.encodeArchiveExportInput(uri.Query(), input)
 
-	res, err := p.c.checkResponseOK(p.c.get(uri.String()))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -288,9 +344,14 @@ func (p *Projects) ArchiveExportAsync(project string, input *ArchiveExportInput)
 
 // ArchiveExportAsyncStatus gets the status of the async archive
 func (p *Projects) ArchiveExportAsyncStatus(project, token string) (*ArchiveExportAsyncStatusResponse, error) {
+	return p.ArchiveExportAsyncStatusWithContext(context.Background(), project, token)
+}
+
+// ArchiveExportAsyncStatusWithContext is the same as ArchiveExportAsyncStatus with the addition of the ability to pass a context.
+func (p *Projects) ArchiveExportAsyncStatusWithContext(ctx context.Context, project, token string) (*ArchiveExportAsyncStatusResponse, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/export/status/" + token
 
-	res, err := p.c.checkResponseOK(p.c.get(rawURL))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -302,7 +363,12 @@ func (p *Projects) ArchiveExportAsyncStatus(project, token string) (*ArchiveExpo
 
 // ArchiveExportAsyncDownload downloads the finished artifact
 func (p *Projects) ArchiveExportAsyncDownload(project, token string) ([]byte, error) {
-	status, err := p.ArchiveExportAsyncStatus(project, token)
+	return p.ArchiveExportAsyncDownloadWithContext(context.Background(), project, token)
+}
+
+// ArchiveExportAsyncDownloadWithContext is the same as ArchiveExportAsyncDownload with the addition of the ability to pass a context.
+func (p *Projects) ArchiveExportAsyncDownloadWithContext(ctx context.Context, project, token string) ([]byte, error) {
+	status, err := p.ArchiveExportAsyncStatusWithContext(ctx, project, token)
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +378,7 @@ func (p *Projects) ArchiveExportAsyncDownload(project, token string) ([]byte, er
 	}
 
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/export/download/" + token
-	res, err := p.c.checkResponseOK(p.c.get(rawURL))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, rawURL))
 	if err != nil {
 		return nil, err
 	}
@@ -323,6 +389,11 @@ func (p *Projects) ArchiveExportAsyncDownload(project, token string) ([]byte, er
 
 // ArchiveImport imports a zip archive into the project
 func (p *Projects) ArchiveImport(project string, content []byte, input *ArchiveImportInput) (*ArchiveImportResponse, error) {
+	return p.ArchiveImportWithContext(context.Background(), project, content, input)
+}
+
+// ArchiveImportWithContext is the same as ArchiveImport with the addition of the ability to pass a context.
+func (p *Projects) ArchiveImportWithContext(ctx context.Context, project string, content []byte, input *ArchiveImportInput) (*ArchiveImportResponse, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/import"
 
 	uri, err := url.Parse(rawURL)
@@ -356,7 +427,7 @@ func (p *Projects) ArchiveImport(project string, content []byte, input *ArchiveI
 		"Content-Type": "application/zip",
 	}
 
-	res, err := p.c.checkResponseOK(p.c.putWithAdditionalHeaders(uri.String(), headers, bytes.NewReader(content)))
+	res, err := p.c.checkResponseOK(p.c.putWithAdditionalHeaders(ctx, uri.String(), headers, bytes.NewReader(content)))
 	if err != nil {
 		return nil, err
 	}
@@ -367,6 +438,11 @@ func (p *Projects) ArchiveImport(project string, content []byte, input *ArchiveI
 
 // ListResources lists resources for a given project
 func (p *Projects) ListResources(project string, nodeFilters map[string]string) (map[string]*NodeEntry, error) {
+	return p.ListResourcesWithContext(context.Background(), project, nodeFilters)
+}
+
+// ListResourcesWithContext is the same as ListResources with the addition of the ability to pass a context.
+func (p *Projects) ListResourcesWithContext(ctx context.Context, project string, nodeFilters map[string]string) (map[string]*NodeEntry, error) {
 	rawURL := p.c.RundeckAddr + "/project/" + project + "/resources"
 
 	uri, err := url.Parse(rawURL)
@@ -382,7 +458,7 @@ func (p *Projects) ListResources(project string, nodeFilters map[string]string)
 
 	uri.RawQuery = query.Encode()
 
-	res, err := p.c.checkResponseOK(p.c.get(uri.String()))
+	res, err := p.c.checkResponseOK(p.c.get(ctx, uri.String()))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,318 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TakeoverScope is how Rebalance moves schedules between cluster members
+type TakeoverScope string
+
+const (
+	TakeoverScopeJob     TakeoverScope = "job"
+	TakeoverScopeProject TakeoverScope = "project"
+)
+
+// RebalanceServer is a cluster member considered by Rebalance
+type RebalanceServer struct {
+	UUID string
+	// Client talks to this member directly, as takeovers are claimed by the server that receives them.
+	// Members without a client are treated as dead.
+	Client *Client
+}
+
+// RebalanceInput are the parameters for ClusterScheduler.Rebalance
+type RebalanceInput struct {
+	// Servers are every member of the cluster, including the dead ones
+	Servers []*RebalanceServer
+
+	// Scope moves schedules one job at a time, or a server's jobs in a project at a time.  Defaults to TakeoverScopeJob.
+	Scope TakeoverScope
+
+	// DryRun computes and prints the plan without running any takeovers
+	DryRun bool
+
+	// Output receives the human readable plan.  Nothing is printed when it is nil.
+	Output io.Writer
+}
+
+// RebalanceServerStatus is the state of a cluster member when the plan was made
+type RebalanceServerStatus struct {
+	UUID          string
+	Available     bool
+	Reason        string
+	ExecutionMode ExecutionMode
+	Jobs          int
+	Target        int
+}
+
+// TakeoverMove moves the schedules of some jobs from one cluster member to another
+type TakeoverMove struct {
+	From    string
+	To      string
+	Project string
+	Jobs    []*Job
+}
+
+// RebalancePlan is the set of takeovers that evens out the schedules across the available members
+type RebalancePlan struct {
+	Scope   TakeoverScope
+	Servers []*RebalanceServerStatus
+	Moves   []*TakeoverMove
+}
+
+// TakeoverSummary aggregates the results of every takeover that was run
+type TakeoverSummary struct {
+	Successful []TakeoverJob
+	Failed     []TakeoverJob
+	Errors     []error
+}
+
+// RebalanceResult is the outcome of ClusterScheduler.Rebalance.  Summary is nil for a dry run.
+type RebalanceResult struct {
+	Plan      *RebalancePlan
+	Summary   *TakeoverSummary
+	Responses []*TakeoverScheduleResponse
+}
+
+// String renders the plan for people to review
+func (p *RebalancePlan) String() string {
+	var b strings.Builder
+	b.WriteString("Cluster members:\n")
+	for _, s := range p.Servers {
+		state := "available"
+		if !s.Available {
+			state = "unavailable (" + s.Reason + ")"
+		}
+		fmt.Fprintf(&b, "  %s %s: %d job(s), target %d\n", s.UUID, state, s.Jobs, s.Target)
+	}
+
+	if len(p.Moves) == 0 {
+		b.WriteString("No takeovers needed\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Takeovers (%d):\n", len(p.Moves))
+	for _, m := range p.Moves {
+		if p.Scope == TakeoverScopeProject {
+			fmt.Fprintf(&b, "  project %s (%d job(s)): %s -> %s\n", m.Project, len(m.Jobs), m.From, m.To)
+			continue
+		}
+		job := m.Jobs[0]
+		fmt.Fprintf(&b, "  %s (%s): %s -> %s\n", joinGroupName(job.Group, job.Name), job.ID, m.From, m.To)
+	}
+	return b.String()
+}
+
+// String summarizes the takeovers
+func (s *TakeoverSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Takeovers: %d successful, %d failed, %d error(s)\n", len(s.Successful), len(s.Failed), len(s.Errors))
+	for _, job := range s.Failed {
+		fmt.Fprintf(&b, "  failed %s (previous owner %s)\n", job.ID, job.PreviousOwner)
+	}
+	for _, err := range s.Errors {
+		fmt.Fprintf(&b, "  error: %v\n", err)
+	}
+	return b.String()
+}
+
+// Rebalance evens out job schedules across the available cluster members.
+//
+// Members that cannot be reached, have no client, or are in passive execution mode are unavailable
+// and have all of their schedules moved.  Available members above their share give up the excess.
+// The schedules are then claimed by running takeovers through the receiving members' clients.
+func (cs *ClusterScheduler) Rebalance(ctx context.Context, input *RebalanceInput) (*RebalanceResult, error) {
+	if input == nil || len(input.Servers) == 0 {
+		return nil, fmt.Errorf("at least one cluster member is required")
+	}
+
+	plan, err := cs.planRebalance(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Output != nil {
+		if _, err := io.WriteString(input.Output, plan.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &RebalanceResult{Plan: plan}
+	if input.DryRun {
+		return result, nil
+	}
+
+	clients := make(map[string]*Client)
+	for _, s := range input.Servers {
+		clients[s.UUID] = s.Client
+	}
+
+	result.Summary = &TakeoverSummary{}
+	for _, move := range plan.Moves {
+		for _, takeover := range move.takeoverInputs(plan.Scope) {
+			res, err := clients[move.To].ClusterScheduler().TakeoverScheduleWithContext(ctx, takeover)
+			if err != nil {
+				result.Summary.Errors = append(result.Summary.Errors, fmt.Errorf("takeover from %s to %s: %v", move.From, move.To, err))
+				continue
+			}
+			result.Responses = append(result.Responses, res)
+			result.Summary.Successful = append(result.Summary.Successful, res.TakeoverSchedule.Jobs.Successful...)
+			result.Summary.Failed = append(result.Summary.Failed, res.TakeoverSchedule.Jobs.Failed...)
+		}
+	}
+
+	if len(result.Summary.Failed) > 0 || len(result.Summary.Errors) > 0 {
+		return result, fmt.Errorf("rebalance incomplete: %d failed takeover(s), %d error(s)", len(result.Summary.Failed), len(result.Summary.Errors))
+	}
+	return result, nil
+}
+
+func (m *TakeoverMove) takeoverInputs(scope TakeoverScope) []*TakeoverScheduleInput {
+	if scope == TakeoverScopeProject {
+		project := m.Project
+		return []*TakeoverScheduleInput{{
+			Server:  &TakeoverServer{UUID: m.From},
+			Project: &project,
+		}}
+	}
+
+	var inputs []*TakeoverScheduleInput
+	for _, job := range m.Jobs {
+		inputs = append(inputs, &TakeoverScheduleInput{
+			Server: &TakeoverServer{UUID: m.From},
+			Job:    &TakeoverJobInput{ID: job.ID},
+		})
+	}
+	return inputs
+}
+
+// rebalanceUnit is a group of jobs that moves together
+type rebalanceUnit struct {
+	from    string
+	project string
+	jobs    []*Job
+}
+
+func (cs *ClusterScheduler) planRebalance(ctx context.Context, input *RebalanceInput) (*RebalancePlan, error) {
+	scope := input.Scope
+	if scope == "" {
+		scope = TakeoverScopeJob
+	}
+	if scope != TakeoverScopeJob && scope != TakeoverScopeProject {
+		return nil, fmt.Errorf("unknown takeover scope %q", scope)
+	}
+
+	plan := &RebalancePlan{Scope: scope}
+	units := make(map[string][]*rebalanceUnit)
+	load := make(map[string]int)
+	total := 0
+
+	for _, server := range input.Servers {
+		status := &RebalanceServerStatus{UUID: server.UUID}
+		plan.Servers = append(plan.Servers, status)
+
+		if server.Client == nil {
+			status.Reason = "no client"
+		} else if info, err := server.Client.System().InfoWithContext(ctx); err != nil {
+			status.Reason = "unreachable: " + err.Error()
+		} else if uuid := info.System.Rundeck.ServerUUID; uuid != server.UUID {
+			return nil, fmt.Errorf("client for %s is connected to server %s", server.UUID, uuid)
+		} else {
+			status.ExecutionMode = info.System.Executions.ExecutionMode
+			status.Available = status.ExecutionMode != ExecutionModePassive
+			if !status.Available {
+				status.Reason = "passive"
+			}
+		}
+
+		uuid := server.UUID
+		jobs, err := cs.ListScheduledJobsWithContext(ctx, &uuid)
+		if err != nil {
+			return nil, err
+		}
+		status.Jobs = len(jobs)
+		load[uuid] = len(jobs)
+		total += len(jobs)
+		units[uuid] = groupRebalanceUnits(uuid, jobs, scope)
+	}
+
+	var available []*RebalanceServerStatus
+	for _, s := range plan.Servers {
+		if s.Available {
+			available = append(available, s)
+		}
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no cluster member is available to take over schedules")
+	}
+
+	// the busiest members keep the remainder, so fewer schedules move
+	sort.SliceStable(available, func(a, b int) bool {
+		if available[a].Jobs != available[b].Jobs {
+			return available[a].Jobs > available[b].Jobs
+		}
+		return available[a].UUID < available[b].UUID
+	})
+	for i, s := range available {
+		s.Target = total / len(available)
+		if i < total%len(available) {
+			s.Target++
+		}
+	}
+
+	var pool []*rebalanceUnit
+	for _, s := range plan.Servers {
+		for _, unit := range units[s.UUID] {
+			if !s.Available || load[s.UUID]-len(unit.jobs) >= s.Target {
+				pool = append(pool, unit)
+				load[s.UUID] -= len(unit.jobs)
+			}
+		}
+	}
+
+	sort.SliceStable(pool, func(a, b int) bool {
+		return len(pool[a].jobs) > len(pool[b].jobs)
+	})
+	for _, unit := range pool {
+		to := available[0]
+		for _, s := range available[1:] {
+			if s.Target-load[s.UUID] > to.Target-load[to.UUID] {
+				to = s
+			}
+		}
+		load[to.UUID] += len(unit.jobs)
+		plan.Moves = append(plan.Moves, &TakeoverMove{
+			From:    unit.from,
+			To:      to.UUID,
+			Project: unit.project,
+			Jobs:    unit.jobs,
+		})
+	}
+
+	return plan, nil
+}
+
+func groupRebalanceUnits(from string, jobs []*Job, scope TakeoverScope) []*rebalanceUnit {
+	sorted := append([]*Job(nil), jobs...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].Project != sorted[b].Project {
+			return sorted[a].Project < sorted[b].Project
+		}
+		return sorted[a].ID < sorted[b].ID
+	})
+
+	var units []*rebalanceUnit
+	for _, job := range sorted {
+		if scope == TakeoverScopeProject && len(units) > 0 && units[len(units)-1].project == job.Project {
+			last := units[len(units)-1]
+			last.jobs = append(last.jobs, job)
+			continue
+		}
+		units = append(units, &rebalanceUnit{from: from, project: job.Project, jobs: []*Job{job}})
+	}
+	return units
+}
@@ -0,0 +1,101 @@
+package rundeck_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestClusterRebalance(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	second := server.AddClusterMember("22222222-2222-2222-2222-222222222222")
+	dead := server.AddClusterMember("33333333-3333-3333-3333-333333333333")
+
+	if _, err := server.Client().Projects().Create(&rundeck.CreateProjectInput{Name: "Cluster"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	// every schedule starts out owned by the member that is about to die
+	var defs []*rundeck.JobDefinition
+	for i := 0; i < 6; i++ {
+		def, _ := rundeck.NewJob(fmt.Sprintf("job-%d", i)).Step(rundeck.Command("uptime")).Schedule("0 0 * ? * *").Build()
+		defs = append(defs, def)
+	}
+	if _, err := dead.Client().Jobs().Sync(ctx, "Cluster", defs, nil); err != nil {
+		t.Fatal("failed to create jobs", err)
+	}
+	dead.Close()
+
+	cli := server.Client()
+	input := &rundeck.RebalanceInput{
+		Servers: []*rundeck.RebalanceServer{
+			{UUID: rundecktest.ServerUUID, Client: cli},
+			{UUID: second.UUID, Client: second.Client()},
+			{UUID: dead.UUID, Client: dead.Client()},
+		},
+		DryRun: true,
+	}
+
+	var out bytes.Buffer
+	input.Output = &out
+	result, err := cli.ClusterScheduler().Rebalance(ctx, input)
+	if err != nil {
+		t.Fatal("failed to plan rebalance", err)
+	}
+	if len(result.Plan.Moves) != 6 || result.Summary != nil {
+		t.Fatalf("unexpected plan:\n%s", result.Plan)
+	}
+	if !strings.Contains(out.String(), dead.UUID+" unavailable (unreachable") {
+		t.Errorf("expected the dead member in the plan:\n%s", out.String())
+	}
+
+	owned, _ := cli.ClusterScheduler().ListScheduledJobs(&dead.UUID)
+	if len(owned) != 6 {
+		t.Errorf("a dry run should not move schedules, %d left on the dead member\n", len(owned))
+	}
+
+	input.DryRun = false
+	input.Output = nil
+	result, err = cli.ClusterScheduler().Rebalance(ctx, input)
+	if err != nil {
+		t.Fatal("failed to rebalance", err)
+	}
+	if len(result.Summary.Successful) != 6 || len(result.Summary.Failed) != 0 {
+		t.Errorf("unexpected summary:\n%s", result.Summary)
+	}
+
+	for _, uuid := range []string{rundecktest.ServerUUID, second.UUID, dead.UUID} {
+		expected := 3
+		if uuid == dead.UUID {
+			expected = 0
+		}
+		jobs, err := cli.ClusterScheduler().ListScheduledJobs(&uuid)
+		if err != nil {
+			t.Fatal("failed to list scheduled jobs", err)
+		}
+		if len(jobs) != expected {
+			t.Errorf("expected %s to own %d schedules, owns %d\n", uuid, expected, len(jobs))
+		}
+	}
+
+	// a passive member hands everything to the remaining active one
+	if _, err := second.Client().System().SetExecutionMode(rundeck.ExecutionModePassive); err != nil {
+		t.Fatal("failed to set execution mode", err)
+	}
+	input.Scope = rundeck.TakeoverScopeProject
+	result, err = cli.ClusterScheduler().Rebalance(ctx, input)
+	if err != nil {
+		t.Fatal("failed to rebalance", err)
+	}
+	if len(result.Plan.Moves) != 1 || result.Plan.Moves[0].From != second.UUID || len(result.Summary.Successful) != 3 {
+		t.Errorf("unexpected plan:\n%s%s", result.Plan, result.Summary)
+	}
+}
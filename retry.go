@@ -0,0 +1,149 @@
+package rundeck
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 250 * time.Millisecond
+	defaultRetryMaxBackoff  = 10 * time.Second
+)
+
+// RetryPolicy configures how the client retries requests that fail transiently,
+// such as a 502 or 503 returned while a Rundeck cluster fails over.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.  Defaults to 3.
+	MaxAttempts int
+
+	// MinBackoff is the base delay before the first retry.  Defaults to 250ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts, including delays requested through Retry-After.  Defaults to 10s.
+	MaxBackoff time.Duration
+
+	// StatusCodes are the response codes considered transient.  Defaults to 429, 502, 503 and 504.
+	StatusCodes []int
+
+	// RetryNonIdempotent allows POST requests to be retried as well.  Only enable this if
+	// replaying a request cannot cause duplicate side effects, such as running a job twice.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy with the default attempts, backoff and status codes
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+		StatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// mayRetry reports whether requests with the given method can be attempted more than once
+func (p *RetryPolicy) mayRetry(method string) bool {
+	if p == nil || p.maxAttempts() < 2 {
+		return false
+	}
+	return p.RetryNonIdempotent || isIdempotent(method)
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return defaultRetryMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, res *http.Response, err error) bool {
+	if !p.mayRetry(method) || ctx.Err() != nil || attempt >= p.maxAttempts() {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	codes := p.StatusCodes
+	if codes == nil {
+		codes = DefaultRetryPolicy().StatusCodes
+	}
+	for _, code := range codes {
+		if res.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt.  A Retry-After header is honored,
+// otherwise an exponential backoff with full jitter is used.
+func (p *RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	minBackoff := p.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultRetryMinBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	if res != nil {
+		if wait, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			if wait > maxBackoff {
+				wait = maxBackoff
+			}
+			return wait
+		}
+	}
+
+	ceiling := maxBackoff
+	if shift := uint(attempt - 1); shift < 32 {
+		if d := minBackoff << shift; d > 0 && d < maxBackoff {
+			ceiling = d
+		}
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package rundecktest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const aclPolicySuffix = ".aclpolicy"
+
+func (s *Server) registerACLRoutes() {
+	s.handle(http.MethodGet, "system/acl", s.listACLs)
+	s.handle(http.MethodGet, "system/acl/{name}", s.getACL)
+	s.handle(http.MethodPost, "system/acl/{name}", s.createACL)
+	s.handle(http.MethodPut, "system/acl/{name}", s.updateACL)
+	s.handle(http.MethodDelete, "system/acl/{name}", s.deleteACL)
+	s.handle(http.MethodGet, "project/{project}/acl", s.listACLs)
+	s.handle(http.MethodGet, "project/{project}/acl/{name}", s.getACL)
+	s.handle(http.MethodPost, "project/{project}/acl/{name}", s.createACL)
+	s.handle(http.MethodPut, "project/{project}/acl/{name}", s.updateACL)
+	s.handle(http.MethodDelete, "project/{project}/acl/{name}", s.deleteACL)
+}
+
+// aclScope holds the policies of either the system or a single project
+type aclScope struct {
+	acls    map[string][]byte
+	href    string
+	project string
+}
+
+// aclScope resolves the optional {project} parameter, responding with a 404 if the project doesn't exist
+func (s *Server) aclScope(w http.ResponseWriter, params map[string]string) (*aclScope, bool) {
+	name, ok := params["project"]
+	if !ok {
+		return &aclScope{acls: s.acls, href: s.URL + "/api/24/system/acl/"}, true
+	}
+
+	p, ok := s.projects[name]
+	if !ok {
+		writeNotFound(w, "Project", name)
+		return nil, false
+	}
+	return &aclScope{acls: p.acls, href: s.URL + "/api/24/project/" + name + "/acl/", project: name}, true
+}
+
+func (s *Server) listACLs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	scope, ok := s.aclScope(w, params)
+	if !ok {
+		return
+	}
+
+	names := make([]string, 0, len(scope.acls))
+	for name := range scope.acls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := rundeck.ListACLsResponse{
+		Path:      "",
+		Type:      "directory",
+		HREF:      scope.href,
+		Resources: make([]*rundeck.ACLResource, 0, len(names)),
+	}
+	for _, name := range names {
+		list.Resources = append(list.Resources, &rundeck.ACLResource{
+			Path: name,
+			Type: "file",
+			Name: name,
+			HREF: scope.href + name,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getACL(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	scope, ok := s.aclScope(w, params)
+	if !ok {
+		return
+	}
+
+	policy, ok := scope.acls[params["name"]]
+	if !ok {
+		writeNotFound(w, "ACL policy", params["name"])
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write(policy)
+}
+
+func (s *Server) createACL(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	scope, ok := s.aclScope(w, params)
+	if !ok {
+		return
+	}
+
+	if _, exists := scope.acls[params["name"]]; exists {
+		writeError(w, http.StatusConflict, "api.error.item.alreadyexists", "ACL policy already exists: "+params["name"])
+		return
+	}
+	s.storeACL(w, r, scope, params["name"], http.StatusCreated)
+}
+
+func (s *Server) updateACL(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	scope, ok := s.aclScope(w, params)
+	if !ok {
+		return
+	}
+
+	if _, exists := scope.acls[params["name"]]; !exists {
+		writeNotFound(w, "ACL policy", params["name"])
+		return
+	}
+	s.storeACL(w, r, scope, params["name"], http.StatusOK)
+}
+
+func (s *Server) storeACL(w http.ResponseWriter, r *http.Request, scope *aclScope, name string, status int) {
+	if !strings.HasSuffix(name, aclPolicySuffix) {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", "ACL policy names must end with "+aclPolicySuffix)
+		return
+	}
+
+	policy, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+	if len(strings.TrimSpace(string(policy))) == 0 {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", "ACL policy content is required")
+		return
+	}
+
+	issues := rundeck.LintACLPolicies(name, policy)
+	if scope.project != "" {
+		issues = rundeck.LintProjectACLPolicies(name, policy)
+	}
+	if rundeck.ACLLintErrors(issues) {
+		writeACLValidation(w, issues)
+		return
+	}
+
+	scope.acls[name] = policy
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	w.Write(policy)
+}
+
+func (s *Server) deleteACL(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	scope, ok := s.aclScope(w, params)
+	if !ok {
+		return
+	}
+
+	if _, ok := scope.acls[params["name"]]; !ok {
+		writeNotFound(w, "ACL policy", params["name"])
+		return
+	}
+
+	delete(scope.acls, params["name"])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeACLValidation responds with the validation errors Rundeck reports for an invalid policy
+func writeACLValidation(w http.ResponseWriter, issues []*rundeck.ACLLintIssue) {
+	var policies []*rundeck.ACLPolicyValidation
+	byPolicy := make(map[string]*rundeck.ACLPolicyValidation)
+	for _, issue := range issues {
+		if issue.Severity != rundeck.ACLLintError {
+			continue
+		}
+		validation, ok := byPolicy[issue.Policy]
+		if !ok {
+			validation = &rundeck.ACLPolicyValidation{Policy: issue.Policy}
+			byPolicy[issue.Policy] = validation
+			policies = append(policies, validation)
+		}
+		message := issue.Message
+		if issue.Path != "" {
+			message = issue.Path + ": " + message
+		}
+		validation.Errors = append(validation.Errors, message)
+	}
+
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"valid":    false,
+		"policies": policies,
+	})
+}
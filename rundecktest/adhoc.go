@@ -0,0 +1,41 @@
+package rundecktest
+
+import (
+	"net/http"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func (s *Server) registerAdhocRoutes() {
+	s.handle(http.MethodPost, "project/{project}/run/command", s.withProject(s.runAdhoc("exec")))
+	s.handle(http.MethodPost, "project/{project}/run/script", s.withProject(s.runAdhoc("script")))
+	s.handle(http.MethodPost, "project/{project}/run/url", s.withProject(s.runAdhoc("url")))
+}
+
+// runAdhoc starts an adhoc execution.  field is the required input field for the endpoint.
+func (s *Server) runAdhoc(field string) projectHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+		var input map[string]interface{}
+		if err := decodeBody(r, &input); err != nil {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+			return
+		}
+
+		value, _ := input[field].(string)
+		if value == "" {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", field+" is required")
+			return
+		}
+
+		user, _ := input["asUser"].(string)
+		if user == "" {
+			user = s.currentUser(r)
+		}
+
+		exec := s.startExecution(p.name, nil, user, "", value)
+		writeJSON(w, http.StatusOK, rundeck.AdhocCommandResponse{
+			Message:   "Immediate execution scheduled (" + exec.view(s).HREF + ")",
+			Execution: *exec.view(s),
+		})
+	}
+}
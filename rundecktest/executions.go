@@ -0,0 +1,499 @@
+package rundecktest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+// ExecutionScript scripts how executions started on the fake progress.  Every time an execution
+// is read through the info, state or output endpoints, it advances one step through Statuses.
+type ExecutionScript struct {
+	// Statuses are the statuses the execution moves through.  The last status is final.
+	Statuses []rundeck.ExecutionStatus
+
+	// Output is revealed progressively as the execution advances, and is complete once
+	// the final status is reached.
+	Output []*rundeck.LogEntry
+}
+
+// DefaultExecutionScript returns a script where executions start running and succeed on the next read
+func DefaultExecutionScript() ExecutionScript {
+	return ExecutionScript{
+		Statuses: []rundeck.ExecutionStatus{rundeck.ExecutionStatusRunning, rundeck.ExecutionStatusSucceeded},
+	}
+}
+
+// SetExecutionScript sets the script followed by executions started after the call
+func (s *Server) SetExecutionScript(script ExecutionScript) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(script.Statuses) == 0 {
+		script.Statuses = DefaultExecutionScript().Statuses
+	}
+	s.script = script
+}
+
+type execution struct {
+	rundeck.Execution
+	jobID        string
+	script       ExecutionScript
+	step         int
+	lastModified time.Time
+}
+
+func (s *Server) startExecution(projectName string, j *job, user, args, description string) *execution {
+	s.nextExecID++
+	now := time.Now()
+
+	exec := &execution{
+		Execution: rundeck.Execution{
+			ID:          s.nextExecID,
+			Project:     projectName,
+			User:        user,
+			ServerUUID:  ServerUUID,
+			Description: description,
+			ArgString:   args,
+			DateStarted: rundeck.ExecutionTimestamp{
+				UnixTime: now.UnixNano() / int64(time.Millisecond),
+				Date:     now,
+			},
+		},
+		script:       s.script,
+		lastModified: now,
+	}
+	if j != nil {
+		exec.jobID = j.ID
+		exec.Job = *s.jobMetadata(j)
+	}
+	exec.setStep(0)
+
+	s.executions[exec.ID] = exec
+	return exec
+}
+
+func (e *execution) final() bool {
+	return e.step >= len(e.script.Statuses)-1
+}
+
+// advance moves the execution to the next scripted status, unless it already finished
+func (e *execution) advance() {
+	if !e.final() {
+		e.setStep(e.step + 1)
+	}
+}
+
+func (e *execution) setStep(step int) {
+	e.step = step
+	e.Status = e.script.Statuses[step]
+	e.lastModified = time.Now()
+	if e.final() {
+		e.finish(e.Status)
+	}
+}
+
+func (e *execution) finish(status rundeck.ExecutionStatus) {
+	e.step = len(e.script.Statuses) - 1
+	e.Status = status
+
+	now := time.Now()
+	e.DateEnded = rundeck.ExecutionTimestamp{
+		UnixTime: now.UnixNano() / int64(time.Millisecond),
+		Date:     now,
+	}
+	e.lastModified = now
+
+	if status == rundeck.ExecutionStatusSucceeded {
+		e.SuccessfulNodes = []string{"localhost"}
+	} else {
+		e.FailedNodes = []string{"localhost"}
+	}
+}
+
+func (e *execution) view(s *Server) *rundeck.Execution {
+	view := e.Execution
+	view.HREF = s.URL + "/api/24/execution/" + strconv.Itoa(e.ID)
+	view.Permalink = s.URL + "/project/" + e.Project + "/execution/show/" + strconv.Itoa(e.ID)
+	return &view
+}
+
+// visibleEntries returns the portion of the scripted output revealed so far
+func (e *execution) visibleEntries() []*rundeck.LogEntry {
+	if e.final() {
+		return e.script.Output
+	}
+	n := len(e.script.Output) * (e.step + 1) / len(e.script.Statuses)
+	return e.script.Output[:n]
+}
+
+func (e *execution) state() rundeck.ExecutionState {
+	switch e.Status {
+	case rundeck.ExecutionStatusRunning:
+		return rundeck.ExecutionStateRunning
+	case rundeck.ExecutionStatusSucceeded:
+		return rundeck.ExecutionStateSucceeded
+	case rundeck.ExecutionStatusAborted:
+		return rundeck.ExecutionStateAborted
+	case rundeck.ExecutionStatusScheduled:
+		return rundeck.ExecutionStateWaiting
+	}
+	return rundeck.ExecutionStateFailed
+}
+
+func (s *Server) registerExecutionRoutes() {
+	s.handle(http.MethodGet, "project/{project}/executions", s.withProject(s.queryExecutions))
+	s.handle(http.MethodGet, "project/{project}/executions/running", s.listRunningExecutions)
+	s.handle(http.MethodGet, "job/{id}/executions", s.withJob(s.listJobExecutions))
+	s.handle(http.MethodDelete, "job/{id}/executions", s.withJob(s.deleteJobExecutions))
+	s.handle(http.MethodDelete, "executions/delete", s.bulkDeleteExecutions)
+	s.handle(http.MethodGet, "execution/{execID}", s.withExecution(s.getExecution))
+	s.handle(http.MethodDelete, "execution/{execID}", s.withExecution(s.deleteExecution))
+	s.handle(http.MethodGet, "execution/{execID}/input/files", s.withExecution(s.listExecutionFiles))
+	s.handle(http.MethodGet, "execution/{execID}/state", s.withExecution(s.getExecutionState))
+	s.handle(http.MethodGet, "execution/{execID}/output", s.withExecution(s.getExecutionOutput))
+	s.handle(http.MethodGet, "execution/{execID}/output/state", s.withExecution(s.getExecutionOutput))
+	s.handle(http.MethodGet, "execution/{execID}/output/node/{node}", s.withExecution(s.getExecutionOutput))
+	s.handle(http.MethodGet, "execution/{execID}/output/step/{step...}", s.withExecution(s.getExecutionOutput))
+	s.handle(http.MethodGet, "execution/{execID}/output/node/{node}/step/{step...}", s.withExecution(s.getExecutionOutput))
+	s.handle(http.MethodGet, "execution/{execID}/abort", s.withExecution(s.abortExecution))
+	s.handle(http.MethodPost, "execution/{execID}/abort", s.withExecution(s.abortExecution))
+}
+
+type executionHandlerFunc func(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string)
+
+// withExecution resolves the {execID} parameter, responding with a 404 if it doesn't exist
+func (s *Server) withExecution(handler executionHandlerFunc) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		id, _ := strconv.Atoi(params["execID"])
+		e, ok := s.executions[id]
+		if !ok {
+			writeNotFound(w, "Execution ID", params["execID"])
+			return
+		}
+		handler(w, r, e, params)
+	}
+}
+
+func (s *Server) sortedExecutions(keep func(e *execution) bool) []*execution {
+	var execs []*execution
+	for _, e := range s.executions {
+		if keep(e) {
+			execs = append(execs, e)
+		}
+	}
+	// newest first, like Rundeck
+	sort.Slice(execs, func(i, j int) bool { return execs[i].ID > execs[j].ID })
+	return execs
+}
+
+func (s *Server) writeExecutions(w http.ResponseWriter, execs []*execution, query url.Values) {
+	page, paging := paginate(len(execs), query)
+
+	views := make([]*rundeck.Execution, 0, page.end-page.start)
+	for _, e := range execs[page.start:page.end] {
+		views = append(views, e.view(s))
+	}
+
+	writeJSON(w, http.StatusOK, rundeck.ExecutionsResponse{
+		PagingInfo: paging,
+		Executions: views,
+	})
+}
+
+func (s *Server) queryExecutions(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	query := r.URL.Query()
+
+	jobIDs := query["jobIdListFilter"]
+	excludeJobIDs := query["excludeJobIdListFilter"]
+
+	execs := s.sortedExecutions(func(e *execution) bool {
+		if e.Project != p.name {
+			return false
+		}
+		if status := query.Get("statusFilter"); status != "" && status != string(e.Status) {
+			return false
+		}
+		if user := query.Get("userFilter"); user != "" && user != e.User {
+			return false
+		}
+		if len(jobIDs) > 0 && !contains(jobIDs, e.jobID) {
+			return false
+		}
+		if contains(excludeJobIDs, e.jobID) {
+			return false
+		}
+		if filter := query.Get("jobFilter"); filter != "" && !strings.Contains(e.Job.Name, filter) {
+			return false
+		}
+		if exact := query.Get("jobExactFilter"); exact != "" && exact != e.Job.Name {
+			return false
+		}
+		if groupPath := query.Get("groupPath"); groupPath != "" && (e.jobID == "" || !matchGroupPath(e.Job.Group, groupPath)) {
+			return false
+		}
+		switch query.Get("adhoc") {
+		case "true":
+			return e.jobID == ""
+		case "false":
+			return e.jobID != ""
+		}
+		return true
+	})
+
+	s.writeExecutions(w, execs, query)
+}
+
+func (s *Server) listRunningExecutions(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	projectName := params["project"]
+	if _, ok := s.projects[projectName]; !ok && projectName != "*" {
+		writeNotFound(w, "Project", projectName)
+		return
+	}
+
+	execs := s.sortedExecutions(func(e *execution) bool {
+		return e.Status == rundeck.ExecutionStatusRunning && (projectName == "*" || e.Project == projectName)
+	})
+
+	s.writeExecutions(w, execs, r.URL.Query())
+}
+
+func (s *Server) listJobExecutions(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	query := r.URL.Query()
+
+	execs := s.sortedExecutions(func(e *execution) bool {
+		if status := query.Get("status"); status != "" && status != string(e.Status) {
+			return false
+		}
+		return e.jobID == j.ID
+	})
+
+	s.writeExecutions(w, execs, query)
+}
+
+func (s *Server) deleteJobExecutions(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	var ids []int
+	for _, e := range s.executions {
+		if e.jobID == j.ID {
+			ids = append(ids, e.ID)
+		}
+	}
+	writeJSON(w, http.StatusOK, s.deleteExecutions(ids))
+}
+
+func (s *Server) bulkDeleteExecutions(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	// Rundeck accepts either a bare array of ids or an object with an ids field
+	var ids []int
+	if err := json.Unmarshal(body, &ids); err != nil {
+		var wrapped struct {
+			IDs []int `json:"ids"`
+		}
+		if err := json.Unmarshal(body, &wrapped); err != nil {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+			return
+		}
+		ids = wrapped.IDs
+	}
+
+	writeJSON(w, http.StatusOK, s.deleteExecutions(ids))
+}
+
+func (s *Server) deleteExecutions(ids []int) *rundeck.DeleteExecutionsResponse {
+	response := &rundeck.DeleteExecutionsResponse{RequestCount: len(ids), AllSuccessful: true}
+	for _, id := range ids {
+		if _, ok := s.executions[id]; !ok {
+			response.AllSuccessful = false
+			response.FailedCount++
+			response.Failures = append(response.Failures, struct {
+				ID      string `json:"id"`
+				Message string `json:"message"`
+			}{ID: strconv.Itoa(id), Message: "Execution Not found: " + strconv.Itoa(id)})
+			continue
+		}
+		delete(s.executions, id)
+		response.SuccessCount++
+	}
+	return response
+}
+
+func (s *Server) getExecution(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string) {
+	e.advance()
+	writeJSON(w, http.StatusOK, e.view(s))
+}
+
+func (s *Server) deleteExecution(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string) {
+	delete(s.executions, e.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listExecutionFiles(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string) {
+	files := make([]*rundeck.FileOption, 0)
+	if j, ok := s.jobs[e.jobID]; ok {
+		for _, f := range j.files {
+			if f.ExecID != nil && *f.ExecID == int64(e.ID) {
+				files = append(files, f)
+			}
+		}
+	}
+
+	page, paging := paginate(len(files), r.URL.Query())
+	writeJSON(w, http.StatusOK, rundeck.UploadedFilesResponse{
+		PagingInfo: paging,
+		File:       files[page.start:page.end],
+	})
+}
+
+func (s *Server) getExecutionState(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string) {
+	e.advance()
+
+	info := rundeck.ExecutionStateInfo{
+		StartTime:      e.DateStarted.Date,
+		EndTime:        e.DateEnded.Date,
+		UpdateTime:     e.lastModified,
+		ExecutionState: e.state(),
+	}
+
+	writeJSON(w, http.StatusOK, rundeck.ExecutionStateResponse{
+		ExecutionStateInfo: info,
+		ExecutionWorkflow: rundeck.ExecutionWorkflow{
+			StepCount:   1,
+			TargetNodes: []string{"localhost"},
+			Steps: []rundeck.ExecutionStepState{{
+				ExecutionStateInfo:    info,
+				ID:                    "1",
+				StepContextIdentifier: "1",
+				NodeStep:              true,
+				NodeStates:            map[string]rundeck.ExecutionStateInfo{"localhost": info},
+			}},
+		},
+		AllNodes:    []string{"localhost"},
+		Nodes:       map[string]rundeck.ExecutionStateIndicator{"localhost": {ExecutionState: e.state(), StepContextIdentifier: "1"}},
+		ServerNode:  "localhost",
+		ExecutionID: e.ID,
+		Completed:   e.Status != rundeck.ExecutionStatusRunning,
+	})
+}
+
+// getExecutionOutput serves log output.  The offset is an index into the entries rather than a byte offset.
+func (s *Server) getExecutionOutput(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string) {
+	query := r.URL.Query()
+
+	lastMod, _ := strconv.ParseInt(query.Get("lastmod"), 10, 64)
+	modified := e.lastModified.UnixNano() / int64(time.Millisecond)
+
+	e.advance()
+	if e.lastModified.UnixNano()/int64(time.Millisecond) > modified {
+		modified = e.lastModified.UnixNano() / int64(time.Millisecond)
+	}
+
+	var entries []*rundeck.LogEntry
+	for _, entry := range e.visibleEntries() {
+		if node := params["node"]; node != "" && entry.Node != node {
+			continue
+		}
+		if step := params["step"]; step != "" && entry.StepContext != step && !strings.HasPrefix(entry.StepContext, step+"/") {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset > len(entries) {
+		offset = len(entries)
+	}
+	if lastLines, _ := strconv.Atoi(query.Get("lastlines")); lastLines > 0 && len(entries)-lastLines > offset {
+		offset = len(entries) - lastLines
+	}
+
+	execCompleted := e.Status != rundeck.ExecutionStatusRunning
+	response := rundeck.ExecutionsOutputResponse{
+		ID:             strconv.Itoa(e.ID),
+		Offset:         strconv.Itoa(len(entries)),
+		Completed:      execCompleted,
+		ExecCompleted:  execCompleted,
+		HasFailedNodes: len(e.FailedNodes) > 0,
+		ExecutionState: e.state(),
+		LastModified:   strconv.FormatInt(modified, 10),
+		PercentLoaded:  100,
+		TotalSize:      len(entries),
+		Entries:        entries[offset:],
+	}
+	if len(response.Entries) == 0 {
+		response.Empty = true
+		response.Entries = []*rundeck.LogEntry{}
+		if lastMod > 0 && lastMod >= modified {
+			response.Unmodified = true
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) abortExecution(w http.ResponseWriter, r *http.Request, e *execution, params map[string]string) {
+	abort := rundeck.Abort{Status: rundeck.AbortStateAborted}
+	if e.Status != rundeck.ExecutionStatusRunning {
+		abort = rundeck.Abort{Status: rundeck.AbortStateFailed, Reason: "Job is not running"}
+	} else {
+		e.finish(rundeck.ExecutionStatusAborted)
+	}
+
+	writeJSON(w, http.StatusOK, rundeck.AbortExecutionResponse{
+		Abort:     abort,
+		Execution: *e.view(s),
+	})
+}
+
+type pageBounds struct {
+	start int
+	end   int
+}
+
+// paginate applies the max and offset query parameters to a result set of the given size
+func paginate(total int, query url.Values) (pageBounds, rundeck.PagingInfo) {
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+
+	max, _ := strconv.Atoi(query.Get("max"))
+	if max <= 0 {
+		max = 20
+	}
+
+	end := offset + max
+	if end > total {
+		end = total
+	}
+
+	return pageBounds{start: offset, end: end}, rundeck.PagingInfo{
+		Count:  end - offset,
+		Total:  total,
+		Max:    max,
+		Offset: offset,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package rundecktest
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/andrewmeissner/go-rundeck"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// jobDocument is a job definition as the server stores it: the YAML document that was imported,
+// with the identifiers and defaults Rundeck adds.  It is kept apart from rundeck.JobDefinition so
+// that exports look like a real server's rather than echoing the client's own model.
+type jobDocument yaml.MapSlice
+
+// parseJobDocuments reads the definitions of an import.  YAML is read as it was written; XML is
+// converted through the rundeck package since the fake has no XML reader of its own.
+func parseJobDocuments(format rundeck.JobFormat, content []byte) ([]jobDocument, error) {
+	if format == rundeck.JobFormatXML {
+		defs, err := rundeck.ParseJobDefinitions(format, content)
+		if err != nil {
+			return nil, err
+		}
+		if content, err = rundeck.MarshalJobDefinitions(rundeck.JobFormatYAML, defs); err != nil {
+			return nil, err
+		}
+	}
+
+	// nested maps are only read in order when decoding into yaml.MapSlice itself
+	var raw []yaml.MapSlice
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	docs := make([]jobDocument, 0, len(raw))
+	for i, doc := range raw {
+		if doc == nil {
+			return nil, fmt.Errorf("job %d is empty", i+1)
+		}
+		docs = append(docs, jobDocument(doc))
+	}
+	return docs, nil
+}
+
+// exportJobDocuments writes stored definitions in the requested format
+func exportJobDocuments(docs []jobDocument, format rundeck.JobFormat) ([]byte, error) {
+	raw := make([]yaml.MapSlice, 0, len(docs))
+	for _, doc := range docs {
+		raw = append(raw, yaml.MapSlice(doc))
+	}
+	content, err := yaml.Marshal(raw)
+	if err != nil || format == rundeck.JobFormatYAML {
+		return content, err
+	}
+
+	defs, err := rundeck.ParseJobDefinitions(rundeck.JobFormatYAML, content)
+	if err != nil {
+		return nil, err
+	}
+	return rundeck.MarshalJobDefinitions(format, defs)
+}
+
+func (d jobDocument) get(key string) (interface{}, bool) {
+	for _, item := range d {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (d jobDocument) string(key string) string {
+	value, ok := d.get(key)
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// bool reads a flag the way Rundeck does, a missing flag being true
+func (d jobDocument) bool(key string) bool {
+	value, ok := d.get(key)
+	return !ok || fmt.Sprint(value) != "false"
+}
+
+func (d *jobDocument) set(key string, value interface{}) {
+	for i, item := range *d {
+		if item.Key == key {
+			(*d)[i].Value = value
+			return
+		}
+	}
+	*d = append(*d, yaml.MapItem{Key: key, Value: value})
+}
+
+func (d *jobDocument) setDefault(key string, value interface{}) {
+	if _, ok := d.get(key); !ok {
+		d.set(key, value)
+	}
+}
+
+func (d *jobDocument) remove(key string) {
+	for i, item := range *d {
+		if item.Key == key {
+			*d = append((*d)[:i], (*d)[i+1:]...)
+			return
+		}
+	}
+}
+
+// section returns the nested map under key, or an empty one when it is missing
+func (d *jobDocument) section(key string) jobDocument {
+	if value, ok := d.get(key); ok {
+		if section, ok := value.(yaml.MapSlice); ok {
+			return jobDocument(section)
+		}
+	}
+	return jobDocument{}
+}
+
+// fillDefaults adds the fields Rundeck fills in when it stores a job, in the shape it exports them
+func (d *jobDocument) fillDefaults(id string) {
+	d.remove("id")
+	d.remove("uuid")
+	*d = append(jobDocument{{Key: "id", Value: id}, {Key: "uuid", Value: id}}, *d...)
+
+	d.setDefault("description", "")
+	d.setDefault("executionEnabled", true)
+	d.setDefault("scheduleEnabled", true)
+	d.setDefault("loglevel", string(rundeck.JobLogLevelInfo))
+
+	sequence := d.section("sequence")
+	sequence.setDefault("keepgoing", false)
+	sequence.setDefault("strategy", string(rundeck.JobSequenceStrategyNodeFirst))
+	d.set("sequence", yaml.MapSlice(sequence))
+
+	if _, ok := d.get("nodefilters"); !ok {
+		return
+	}
+	d.setDefault("nodesSelectedByDefault", true)
+	filters := d.section("nodefilters")
+	dispatch := filters.section("dispatch")
+	dispatch.setDefault("excludePrecedence", true)
+	dispatch.setDefault("keepgoing", false)
+	dispatch.setDefault("rankOrder", "ascending")
+	dispatch.setDefault("successOnEmptyNodeFilter", false)
+	// Rundeck exports the thread count as a quoted string
+	threadCount := "1"
+	if value, ok := dispatch.get("threadcount"); ok {
+		threadCount = fmt.Sprint(value)
+	}
+	if _, err := strconv.Atoi(threadCount); err == nil {
+		dispatch.set("threadcount", threadCount)
+	}
+	filters.set("dispatch", yaml.MapSlice(dispatch))
+	d.set("nodefilters", yaml.MapSlice(filters))
+}
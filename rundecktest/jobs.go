@@ -0,0 +1,640 @@
+package rundecktest
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+type job struct {
+	rundeck.Job
+	format     rundeck.JobFormat
+	definition jobDocument
+	files      []*rundeck.FileOption
+}
+
+// SetJobAverageDuration sets the average duration reported for a job, as Rundeck computes it from past executions
+func (s *Server) SetJobAverageDuration(id string, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j, ok := s.jobs[id]; ok {
+		j.AverageDuration = int64(duration / time.Millisecond)
+	}
+}
+
+func (s *Server) registerJobRoutes() {
+	s.handle(http.MethodGet, "project/{project}/jobs", s.withProject(s.listJobs))
+	s.handle(http.MethodGet, "project/{project}/jobs/export", s.withProject(s.exportProjectJobs))
+	s.handle(http.MethodPost, "project/{project}/jobs/import", s.withProject(s.importProjectJobs))
+	s.handle(http.MethodGet, "job/{id}", s.withJob(s.getJobDefinition))
+	s.handle(http.MethodDelete, "job/{id}", s.withJob(s.deleteJob))
+	s.handle(http.MethodGet, "job/{id}/info", s.withJob(s.getJobInfo))
+	s.handle(http.MethodPost, "job/{id}/run", s.withJob(s.runJob))
+	s.handle(http.MethodPost, "job/{id}/retry/{execID}", s.withJob(s.retryJob))
+	s.handle(http.MethodPost, "job/{id}/input/file", s.withJob(s.uploadJobFile))
+	s.handle(http.MethodGet, "job/{id}/input/files", s.withJob(s.listJobFiles))
+	s.handle(http.MethodPost, "job/{id}/{kind}/{toggle}", s.withJob(s.toggleJob))
+	s.handle(http.MethodGet, "jobs/file/{fileID}", s.getJobFile)
+	s.handle(http.MethodDelete, "jobs/delete", s.bulkDeleteJobs)
+	s.handle(http.MethodPost, "jobs/{kind}/{toggle}", s.bulkToggleJobs)
+	s.handle(http.MethodGet, "scheduler/jobs", s.listScheduledJobs)
+	s.handle(http.MethodGet, "scheduler/server/{uuid}/jobs", s.listScheduledJobs)
+	s.handle(http.MethodPut, "scheduler/takeover", s.takeoverSchedule)
+}
+
+type jobHandlerFunc func(w http.ResponseWriter, r *http.Request, j *job, params map[string]string)
+
+// withJob resolves the {id} parameter, responding with a 404 if it doesn't exist
+func (s *Server) withJob(handler jobHandlerFunc) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		j, ok := s.jobs[params["id"]]
+		if !ok {
+			writeNotFound(w, "Job ID", params["id"])
+			return
+		}
+		handler(w, r, j, params)
+	}
+}
+
+func (s *Server) projectJobs(project string) []*job {
+	var jobs []*job
+	for _, j := range s.jobs {
+		if j.Project == project {
+			jobs = append(jobs, j)
+		}
+	}
+	sortJobs(jobs)
+	return jobs
+}
+
+func sortJobs(jobs []*job) {
+	sort.Slice(jobs, func(i, k int) bool {
+		if jobs[i].Group != jobs[k].Group {
+			return jobs[i].Group < jobs[k].Group
+		}
+		if jobs[i].Name != jobs[k].Name {
+			return jobs[i].Name < jobs[k].Name
+		}
+		return jobs[i].ID < jobs[k].ID
+	})
+}
+
+func (s *Server) jobMetadata(j *job) *rundeck.Job {
+	meta := j.Job
+	meta.HREF = s.URL + "/api/24/job/" + j.ID
+	meta.Permalink = s.URL + "/project/" + j.Project + "/job/show/" + j.ID
+	meta.ServerOwner = meta.ServerNodeUUID == ServerUUID
+	return &meta
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	query := r.URL.Query()
+	jobs := make([]*rundeck.Job, 0)
+
+	var ids map[string]bool
+	if idlist := query.Get("idlist"); idlist != "" {
+		ids = make(map[string]bool)
+		for _, id := range strings.Split(idlist, ",") {
+			ids[id] = true
+		}
+	}
+
+	for _, j := range s.projectJobs(p.name) {
+		if ids != nil && !ids[j.ID] {
+			continue
+		}
+		if !matchGroupPath(j.Group, query.Get("groupPath")) {
+			continue
+		}
+		if exact := query.Get("groupPathExact"); exact != "" && exact != j.Group && !(exact == "-" && j.Group == "") {
+			continue
+		}
+		if filter := query.Get("jobFilter"); filter != "" && !strings.Contains(j.Name, filter) {
+			continue
+		}
+		if exact := query.Get("jobExactFilter"); exact != "" && exact != j.Name {
+			continue
+		}
+		if query.Get("scheduledFilter") == "true" && !j.Scheduled {
+			continue
+		}
+		if uuid := query.Get("serverNodeUUIDFilter"); uuid != "" && uuid != j.ServerNodeUUID {
+			continue
+		}
+		jobs = append(jobs, s.jobMetadata(j))
+	}
+
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+func matchGroupPath(group, groupPath string) bool {
+	switch groupPath {
+	case "", "*":
+		return true
+	case "-":
+		return group == ""
+	}
+	return group == groupPath || strings.HasPrefix(group, groupPath+"/")
+}
+
+func (s *Server) exportProjectJobs(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	query := r.URL.Query()
+
+	format := rundeck.JobFormatXML
+	if query.Get("format") == string(rundeck.JobFormatYAML) {
+		format = rundeck.JobFormatYAML
+	}
+
+	var ids map[string]bool
+	if idlist := query.Get("idlist"); idlist != "" {
+		ids = make(map[string]bool)
+		for _, id := range strings.Split(idlist, ",") {
+			ids[id] = true
+		}
+	}
+
+	var jobs []*job
+	for _, j := range s.projectJobs(p.name) {
+		if ids != nil && !ids[j.ID] {
+			continue
+		}
+		if !matchGroupPath(j.Group, query.Get("groupPath")) {
+			continue
+		}
+		if filter := query.Get("jobFilter"); filter != "" && !strings.Contains(j.Name, filter) {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+
+	writeJobDefinitions(w, jobs, format)
+}
+
+func (s *Server) getJobDefinition(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	format := rundeck.JobFormatXML
+	if r.URL.Query().Get("format") == string(rundeck.JobFormatYAML) {
+		format = rundeck.JobFormatYAML
+	}
+
+	writeJobDefinitions(w, []*job{j}, format)
+}
+
+func writeJobDefinitions(w http.ResponseWriter, jobs []*job, format rundeck.JobFormat) {
+	content, err := exportJobs(jobs, format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	contentType := "application/xml"
+	if format == rundeck.JobFormatYAML {
+		contentType = "application/yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(content)
+}
+
+// exportJobs serializes the stored definitions in the requested format
+func exportJobs(jobs []*job, format rundeck.JobFormat) ([]byte, error) {
+	docs := make([]jobDocument, 0, len(jobs))
+	for _, j := range jobs {
+		docs = append(docs, j.definition)
+	}
+	return exportJobDocuments(docs, format)
+}
+
+func (s *Server) importProjectJobs(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	query := r.URL.Query()
+
+	format := rundeck.JobFormatXML
+	if query.Get("fileformat") == string(rundeck.JobFormatYAML) || query.Get("format") == string(rundeck.JobFormatYAML) {
+		format = rundeck.JobFormatYAML
+	}
+
+	dupe := rundeck.DuplicateOption(query.Get("dupeOption"))
+	if dupe == "" {
+		dupe = rundeck.DuplicateOptionCreate
+	}
+
+	content, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	response, err := s.importJobs(p.name, memberUUID(r), format, content, dupe, rundeck.UUIDOption(query.Get("uuidOption")))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.jobs.import.invalid", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// importJobs stores the definitions in content, following Rundeck's duplicate and uuid handling
+func (s *Server) importJobs(projectName, owner string, format rundeck.JobFormat, content []byte, dupe rundeck.DuplicateOption, uuidOption rundeck.UUIDOption) (*rundeck.ImportJobsResponse, error) {
+	parsed, err := parseJobDocuments(format, content)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &rundeck.ImportJobsResponse{
+		Succeeded: []*rundeck.Job{},
+		Failed:    []*rundeck.Job{},
+		Skipped:   []*rundeck.Job{},
+	}
+
+	for i, def := range parsed {
+		uuid := def.string("uuid")
+		if uuid == "" {
+			uuid = def.string("id")
+		}
+		if uuidOption == rundeck.UUIDOptionRemove {
+			uuid = ""
+		}
+		name, group := def.string("name"), def.string("group")
+
+		result := &rundeck.Job{Index: i + 1, Name: name, Group: group, Project: projectName}
+
+		if name == "" {
+			result.Description = "job name is required"
+			response.Failed = append(response.Failed, result)
+			continue
+		}
+
+		existing := s.findJob(projectName, uuid, group, name)
+		if existing != nil && existing.Project != projectName {
+			result.Description = "a job with uuid " + existing.ID + " already exists in project " + existing.Project
+			response.Failed = append(response.Failed, result)
+			continue
+		}
+
+		if existing != nil && dupe == rundeck.DuplicateOptionSkip {
+			result.ID = existing.ID
+			response.Skipped = append(response.Skipped, result)
+			continue
+		}
+
+		if existing != nil && dupe == rundeck.DuplicateOptionCreate && uuid != "" {
+			result.ID = existing.ID
+			result.Description = "a job with uuid " + existing.ID + " already exists"
+			response.Failed = append(response.Failed, result)
+			continue
+		}
+
+		j := existing
+		if j == nil || dupe == rundeck.DuplicateOptionCreate {
+			id := uuid
+			if id == "" {
+				id = newUUID()
+			}
+			j = &job{Job: rundeck.Job{ID: id, Project: projectName, ServerNodeUUID: owner}}
+			s.jobs[id] = j
+		}
+
+		def.fillDefaults(j.ID)
+		_, scheduled := def.get("schedule")
+		j.Name = name
+		j.Group = group
+		j.Description = def.string("description")
+		j.Scheduled = scheduled
+		j.ScheduleEnabled = def.bool("scheduleEnabled")
+		j.Enabled = def.bool("executionEnabled")
+		j.format = format
+		j.definition = def
+
+		result.ID = j.ID
+		result.HREF = s.jobMetadata(j).HREF
+		result.Permalink = s.jobMetadata(j).Permalink
+		response.Succeeded = append(response.Succeeded, result)
+	}
+
+	return response, nil
+}
+
+// findJob matches on uuid when the definition has one, otherwise on project, group and name
+func (s *Server) findJob(projectName, uuid, group, name string) *job {
+	if uuid != "" {
+		return s.jobs[uuid]
+	}
+	for _, j := range s.projectJobs(projectName) {
+		if j.Group == group && j.Name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+func (s *Server) deleteJob(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	delete(s.jobs, j.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getJobInfo(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	writeJSON(w, http.StatusOK, s.jobMetadata(j))
+}
+
+func (s *Server) runJob(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	var input struct {
+		LogLevel  rundeck.LogLevel  `json:"loglevel"`
+		AsUser    string            `json:"asUser"`
+		Filter    string            `json:"filter"`
+		RunAtTime *time.Time        `json:"runAtTime"`
+		Options   map[string]string `json:"options"`
+	}
+	if r.ContentLength != 0 {
+		if err := decodeBody(r, &input); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+			return
+		}
+	}
+
+	if !j.Enabled {
+		writeError(w, http.StatusBadRequest, "api.error.execution.disabled", "Job execution is disabled: "+j.ID)
+		return
+	}
+
+	user := input.AsUser
+	if user == "" {
+		user = s.currentUser(r)
+	}
+
+	exec := s.startExecution(j.Project, j, user, argString(input.Options), j.Description)
+	writeJSON(w, http.StatusOK, exec.view(s))
+}
+
+func (s *Server) retryJob(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	execID, _ := strconv.Atoi(params["execID"])
+	previous, ok := s.executions[execID]
+	if !ok {
+		writeNotFound(w, "Execution ID", params["execID"])
+		return
+	}
+
+	var input rundeck.RetryJobInput
+	if r.ContentLength != 0 {
+		if err := decodeBody(r, &input); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+			return
+		}
+	}
+
+	args := previous.ArgString
+	if input.Options != nil {
+		args = argString(input.Options)
+	}
+
+	user := input.AsUser
+	if user == "" {
+		user = s.currentUser(r)
+	}
+
+	exec := s.startExecution(j.Project, j, user, args, j.Description)
+	writeJSON(w, http.StatusOK, exec.view(s))
+}
+
+func argString(options map[string]string) string {
+	keys := sortedKeys(options)
+	args := make([]string, 0, len(keys))
+	for _, k := range keys {
+		args = append(args, "-"+k+" "+options[k])
+	}
+	return strings.Join(args, " ")
+}
+
+func (s *Server) toggleJob(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	enabled, ok := parseToggle(params["kind"], params["toggle"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "api.error.invalid.request", "unknown endpoint: "+r.URL.Path)
+		return
+	}
+
+	setToggle(j, rundeck.ToggleKind(params["kind"]), enabled)
+	writeJSON(w, http.StatusOK, rundeck.SuccessResponse{Success: true})
+}
+
+func (s *Server) bulkToggleJobs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	enabled, ok := parseToggle(params["kind"], params["toggle"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "api.error.invalid.request", "unknown endpoint: "+r.URL.Path)
+		return
+	}
+
+	var input rundeck.BulkModifyInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	response := s.bulkModify(input.IDs, func(j *job) {
+		setToggle(j, rundeck.ToggleKind(params["kind"]), enabled)
+	})
+	response.Enabled = enabled
+	writeJSON(w, http.StatusOK, response)
+}
+
+func parseToggle(kind, toggle string) (bool, bool) {
+	if kind != string(rundeck.ToggleKindExecution) && kind != string(rundeck.ToggleKindSchedule) {
+		return false, false
+	}
+	switch toggle {
+	case "enable":
+		return true, true
+	case "disable":
+		return false, true
+	}
+	return false, false
+}
+
+func setToggle(j *job, kind rundeck.ToggleKind, enabled bool) {
+	if kind == rundeck.ToggleKindSchedule {
+		j.ScheduleEnabled = enabled
+		j.definition.set("scheduleEnabled", enabled)
+	} else {
+		j.Enabled = enabled
+		j.definition.set("executionEnabled", enabled)
+	}
+}
+
+func (s *Server) bulkDeleteJobs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var input rundeck.BulkModifyInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.bulkModify(input.IDs, func(j *job) {
+		delete(s.jobs, j.ID)
+	}))
+}
+
+func (s *Server) bulkModify(ids []string, modify func(j *job)) *rundeck.BulkModifyResponse {
+	response := &rundeck.BulkModifyResponse{RequestCount: len(ids), AllSuccessful: true}
+	for _, id := range ids {
+		j, ok := s.jobs[id]
+		if !ok {
+			response.AllSuccessful = false
+			response.Failed = append(response.Failed, &rundeck.BulkModifyObject{
+				ID:        id,
+				ErrorCode: "api.error.item.doesnotexist",
+				Message:   "Job ID does not exist: " + id,
+			})
+			continue
+		}
+		modify(j)
+		response.Succeeded = append(response.Succeeded, &rundeck.BulkModifyObject{ID: id})
+	}
+	return response
+}
+
+func (s *Server) uploadJobFile(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	content, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	optionName := query.Get("optionName")
+	if optionName == "" {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", "optionName is required")
+		return
+	}
+
+	file := &rundeck.FileOption{
+		ID:             newUUID(),
+		User:           s.currentUser(r),
+		FileState:      rundeck.FileStateTemp,
+		JobID:          j.ID,
+		DateCreated:    time.Now(),
+		ServerNodeUUID: memberUUID(r),
+		Size:           int64(len(content)),
+		ExpirationDate: time.Now().Add(time.Hour),
+	}
+	if name := query.Get("fileName"); name != "" {
+		file.FileName = &name
+	}
+	j.files = append(j.files, file)
+
+	writeJSON(w, http.StatusOK, rundeck.UploadFileResponse{
+		Total:   1,
+		Options: map[string]string{optionName: file.ID},
+	})
+}
+
+func (s *Server) listJobFiles(w http.ResponseWriter, r *http.Request, j *job, params map[string]string) {
+	query := r.URL.Query()
+
+	files := make([]*rundeck.FileOption, 0)
+	for _, f := range j.files {
+		if state := query.Get("fileState"); state != "" && state != string(f.FileState) {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	page, paging := paginate(len(files), query)
+	writeJSON(w, http.StatusOK, rundeck.UploadedFilesResponse{
+		PagingInfo: paging,
+		File:       files[page.start:page.end],
+	})
+}
+
+func (s *Server) getJobFile(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	for _, j := range s.jobs {
+		for _, f := range j.files {
+			if f.ID == params["fileID"] {
+				writeJSON(w, http.StatusOK, f)
+				return
+			}
+		}
+	}
+	writeNotFound(w, "File", params["fileID"])
+}
+
+func (s *Server) listScheduledJobs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	uuid := params["uuid"]
+	if uuid == "" {
+		uuid = memberUUID(r)
+	}
+
+	var all []*job
+	for _, j := range s.jobs {
+		all = append(all, j)
+	}
+	sortJobs(all)
+
+	jobs := make([]*rundeck.Job, 0)
+	for _, j := range all {
+		if j.Scheduled && j.ServerNodeUUID == uuid {
+			jobs = append(jobs, s.jobMetadata(j))
+		}
+	}
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+func (s *Server) takeoverSchedule(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var input rundeck.TakeoverScheduleInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	response := rundeck.TakeoverScheduleResponse{
+		Message:    "Schedule Takeover successful",
+		APIVersion: rundeck.APIVersion24,
+		Success:    true,
+	}
+	response.Self.Server.UUID = memberUUID(r)
+	response.TakeoverSchedule.Jobs.Successful = []rundeck.TakeoverJob{}
+	response.TakeoverSchedule.Jobs.Failed = []rundeck.TakeoverJob{}
+
+	if input.Server != nil {
+		response.TakeoverSchedule.Server = *input.Server
+	}
+	if input.Project != nil {
+		response.TakeoverSchedule.Project = *input.Project
+	}
+
+	var all []*job
+	for _, j := range s.jobs {
+		all = append(all, j)
+	}
+	sortJobs(all)
+
+	for _, j := range all {
+		if !j.Scheduled {
+			continue
+		}
+		if input.Job != nil && input.Job.ID != "" && input.Job.ID != j.ID {
+			continue
+		}
+		if input.Project != nil && *input.Project != j.Project {
+			continue
+		}
+		if input.Server != nil && !input.Server.All && input.Server.UUID != "" && input.Server.UUID != j.ServerNodeUUID {
+			continue
+		}
+		if input.Server == nil && input.Job == nil && input.Project == nil {
+			continue
+		}
+
+		meta := s.jobMetadata(j)
+		response.TakeoverSchedule.Jobs.Successful = append(response.TakeoverSchedule.Jobs.Successful, rundeck.TakeoverJob{
+			HREF:          meta.HREF,
+			Permalink:     meta.Permalink,
+			ID:            j.ID,
+			PreviousOwner: j.ServerNodeUUID,
+		})
+		j.ServerNodeUUID = memberUUID(r)
+	}
+	response.TakeoverSchedule.Jobs.Total = len(response.TakeoverSchedule.Jobs.Successful)
+
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,349 @@
+package rundecktest
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const projectDescriptionKey = "project.description"
+
+type project struct {
+	name   string
+	config map[string]string
+	acls   map[string][]byte
+}
+
+func (p *project) info(s *Server) rundeck.ProjectInfo {
+	config := make(map[string]string, len(p.config))
+	for k, v := range p.config {
+		config[k] = v
+	}
+
+	return rundeck.ProjectInfo{
+		Project: rundeck.Project{
+			Name:        p.name,
+			Description: p.config[projectDescriptionKey],
+			URL:         s.URL + "/api/24/project/" + p.name,
+		},
+		Config: config,
+	}
+}
+
+func (s *Server) registerProjectRoutes() {
+	s.handle(http.MethodGet, "projects", s.listProjects)
+	s.handle(http.MethodPost, "projects", s.createProject)
+	s.handle(http.MethodGet, "project/{project}", s.withProject(s.getProject))
+	s.handle(http.MethodDelete, "project/{project}", s.withProject(s.deleteProject))
+	s.handle(http.MethodGet, "project/{project}/config", s.withProject(s.getProjectConfig))
+	s.handle(http.MethodPut, "project/{project}/config", s.withProject(s.putProjectConfig))
+	s.handle(http.MethodGet, "project/{project}/config/{key}", s.withProject(s.getProjectConfigKey))
+	s.handle(http.MethodPut, "project/{project}/config/{key}", s.withProject(s.putProjectConfigKey))
+	s.handle(http.MethodDelete, "project/{project}/config/{key}", s.withProject(s.deleteProjectConfigKey))
+	s.handle(http.MethodGet, "project/{project}/export", s.withProject(s.exportProject))
+	s.handle(http.MethodGet, "project/{project}/export/async", s.withProject(s.exportProjectAsync))
+	s.handle(http.MethodGet, "project/{project}/export/status/{token}", s.withProject(s.exportProjectStatus))
+	s.handle(http.MethodGet, "project/{project}/export/download/{token}", s.withProject(s.exportProjectDownload))
+	s.handle(http.MethodPut, "project/{project}/import", s.withProject(s.importProject))
+	s.handle(http.MethodGet, "project/{project}/resources", s.withProject(s.listResources))
+}
+
+type projectHandlerFunc func(w http.ResponseWriter, r *http.Request, p *project, params map[string]string)
+
+// withProject resolves the {project} parameter, responding with a 404 if it doesn't exist
+func (s *Server) withProject(handler projectHandlerFunc) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		p, ok := s.projects[params["project"]]
+		if !ok {
+			writeNotFound(w, "Project", params["project"])
+			return
+		}
+		handler(w, r, p, params)
+	}
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	projects := make([]*rundeck.Project, 0, len(s.projects))
+	for _, p := range s.projects {
+		info := p.info(s)
+		projects = append(projects, &info.Project)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var input rundeck.CreateProjectInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	if input.Name == "" {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", "project name is required")
+		return
+	}
+
+	if _, exists := s.projects[input.Name]; exists {
+		writeError(w, http.StatusConflict, "api.error.item.alreadyexists", "Project already exists: "+input.Name)
+		return
+	}
+
+	p := &project{
+		name:   input.Name,
+		config: map[string]string{"project.name": input.Name},
+		acls:   make(map[string][]byte),
+	}
+	for k, v := range input.Config {
+		p.config[k] = v
+	}
+	if input.Description != "" {
+		p.config[projectDescriptionKey] = input.Description
+	}
+
+	s.projects[p.name] = p
+	writeJSON(w, http.StatusCreated, p.info(s))
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	writeJSON(w, http.StatusOK, p.info(s))
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	for id, j := range s.jobs {
+		if j.Project == p.name {
+			delete(s.jobs, id)
+		}
+	}
+	for id, exec := range s.executions {
+		if exec.Project == p.name {
+			delete(s.executions, id)
+		}
+	}
+	delete(s.projects, p.name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getProjectConfig(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	writeJSON(w, http.StatusOK, p.info(s).Config)
+}
+
+func (s *Server) putProjectConfig(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	var config map[string]string
+	if err := decodeBody(r, &config); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	p.config = map[string]string{"project.name": p.name}
+	for k, v := range config {
+		p.config[k] = v
+	}
+	writeJSON(w, http.StatusOK, p.info(s).Config)
+}
+
+func (s *Server) getProjectConfigKey(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	value, ok := p.config[params["key"]]
+	if !ok {
+		writeNotFound(w, "Project configuration key", params["key"])
+		return
+	}
+	writeJSON(w, http.StatusOK, rundeck.ProjectConfigKeyPair{Key: params["key"], Value: value})
+}
+
+func (s *Server) putProjectConfigKey(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	var pair rundeck.ProjectConfigKeyPair
+	if err := decodeBody(r, &pair); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	pair.Key = params["key"]
+	p.config[pair.Key] = pair.Value
+	writeJSON(w, http.StatusOK, pair)
+}
+
+func (s *Server) deleteProjectConfigKey(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	delete(p.config, params["key"])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) exportProject(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	archive, err := s.projectArchive(p)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "api.error.unknown", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Write(archive)
+}
+
+func (s *Server) exportProjectAsync(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	archive, err := s.projectArchive(p)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "api.error.unknown", err.Error())
+		return
+	}
+
+	token := newToken()
+	s.exports[token] = archive
+	writeJSON(w, http.StatusOK, rundeck.ArchiveExportAsyncStatusResponse{Token: token, Ready: true, Percentage: 100})
+}
+
+func (s *Server) exportProjectStatus(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	if _, ok := s.exports[params["token"]]; !ok {
+		writeNotFound(w, "Export request token", params["token"])
+		return
+	}
+	writeJSON(w, http.StatusOK, rundeck.ArchiveExportAsyncStatusResponse{Token: params["token"], Ready: true, Percentage: 100})
+}
+
+func (s *Server) exportProjectDownload(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	archive, ok := s.exports[params["token"]]
+	if !ok {
+		writeNotFound(w, "Export request token", params["token"])
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Write(archive)
+}
+
+// projectArchive builds a zip laid out like a Rundeck project archive
+func (s *Server) projectArchive(p *project) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	root := "rundeck-" + p.name + "/"
+
+	var props strings.Builder
+	for _, k := range sortedKeys(p.config) {
+		props.WriteString(k + "=" + p.config[k] + "\n")
+	}
+	if err := writeZipFile(zw, root+"files/etc/project.properties", []byte(props.String())); err != nil {
+		return nil, err
+	}
+
+	for _, j := range s.projectJobs(p.name) {
+		content, err := exportJobs([]*job{j}, j.format)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeZipFile(zw, root+"jobs/job-"+j.ID+"."+string(j.format), content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Server) importProject(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	content, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", "invalid project archive: "+err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	uuidOption := rundeck.UUIDOption(query.Get("jobUuidOption"))
+	response := rundeck.ArchiveImportResponse{ImportStatus: rundeck.StatusSuccessful}
+
+	for _, f := range zr.File {
+		data, err := readZipFile(f)
+		if err != nil {
+			response.Errors = append(response.Errors, err.Error())
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(f.Name, "files/etc/project.properties") && query.Get("importConfig") == "true":
+			for k, v := range parseProperties(data) {
+				if k != "project.name" {
+					p.config[k] = v
+				}
+			}
+		case path.Base(path.Dir(f.Name)) == "jobs":
+			format := rundeck.JobFormat(strings.TrimPrefix(path.Ext(f.Name), "."))
+			if _, err := s.importJobs(p.name, memberUUID(r), format, data, rundeck.DuplicateOptionCreate, uuidOption); err != nil {
+				response.Errors = append(response.Errors, f.Name+": "+err.Error())
+			}
+		}
+	}
+
+	if len(response.Errors) > 0 {
+		response.ImportStatus = rundeck.StatusFailed
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// listResources returns the fake's single server node.  Node filters are accepted but not evaluated.
+func (s *Server) listResources(w http.ResponseWriter, r *http.Request, p *project, params map[string]string) {
+	writeJSON(w, http.StatusOK, map[string]*rundeck.NodeEntry{
+		"localhost": {
+			Nodename:    "localhost",
+			Hostname:    "localhost",
+			Username:    DefaultUser,
+			Description: "Rundeck server node",
+			OSFamily:    "unix",
+		},
+	})
+}
+
+func writeZipFile(zw *zip.Writer, name string, content []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+func parseProperties(data []byte) map[string]string {
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, "="); i >= 0 {
+			props[line[:i]] = line[i+1:]
+		}
+	}
+	return props
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,345 @@
+// Package rundecktest provides an in-memory fake of the Rundeck API for offline testing.
+//
+// The fake implements the API v24 endpoints used by the rundeck package.  Projects, jobs,
+// executions, key storage, ACLs, tokens and users keep their state in memory for the lifetime
+// of the Server, so tests can exercise the client without a live Rundeck instance.
+package rundecktest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const (
+	// DefaultToken is the API token that every new Server accepts
+	DefaultToken = "rundecktest-token"
+
+	// DefaultTokenID is the id of DefaultToken in the token API
+	DefaultTokenID = "rundecktest-token-id"
+
+	// DefaultUser is the user that owns DefaultToken
+	DefaultUser = "admin"
+
+	// ServerUUID is the cluster server uuid reported by the fake
+	ServerUUID = "8e4d9a3c-2f5b-4a61-9c7e-0a1b2c3d4e5f"
+
+	apiPrefix = "/api/"
+)
+
+// Server is a fake Rundeck server backed by an httptest.Server
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	routes     []route
+	projects   map[string]*project
+	jobs       map[string]*job
+	executions map[int]*execution
+	nextExecID int
+	keys       map[string]*storedKey
+	acls       map[string][]byte
+	tokens     map[string]*rundeck.Token
+	users      map[string]*rundeck.UserProfile
+	modes      map[string]rundeck.ExecutionMode
+	members    []*ClusterMember
+	script     ExecutionScript
+	exports    map[string][]byte
+
+	maxTokenDuration time.Duration
+	incompleteLogs   map[int]bool
+}
+
+// NewServer starts a fake Rundeck server.  Callers should Close it when finished.
+func NewServer() *Server {
+	s := &Server{
+		projects:   make(map[string]*project),
+		jobs:       make(map[string]*job),
+		executions: make(map[int]*execution),
+		keys:       make(map[string]*storedKey),
+		acls:       make(map[string][]byte),
+		tokens:     make(map[string]*rundeck.Token),
+		users:      make(map[string]*rundeck.UserProfile),
+		modes:      make(map[string]rundeck.ExecutionMode),
+		script:     DefaultExecutionScript(),
+		exports:    make(map[string][]byte),
+	}
+
+	s.users[DefaultUser] = &rundeck.UserProfile{Login: DefaultUser}
+	s.tokens[DefaultTokenID] = &rundeck.Token{
+		ID:         DefaultTokenID,
+		Token:      DefaultToken,
+		User:       DefaultUser,
+		Creator:    DefaultUser,
+		Roles:      []string{"admin"},
+		Expiration: time.Now().AddDate(1, 0, 0),
+	}
+
+	s.registerRoutes()
+	s.Server = httptest.NewServer(s.memberHandler(ServerUUID))
+	return s
+}
+
+// ClusterMember is another server in a fake cluster.  It shares the state of the Server that
+// started it, but reports its own server uuid and execution mode, and owns the schedules of the
+// jobs created or taken over through it.
+type ClusterMember struct {
+	*httptest.Server
+
+	// UUID is the cluster server uuid reported by the member
+	UUID string
+}
+
+// AddClusterMember starts another cluster member with the given server uuid.  It is closed along
+// with s, or can be closed on its own to simulate a dead server.
+func (s *Server) AddClusterMember(uuid string) *ClusterMember {
+	member := &ClusterMember{
+		Server: httptest.NewServer(s.memberHandler(uuid)),
+		UUID:   uuid,
+	}
+
+	s.mu.Lock()
+	s.members = append(s.members, member)
+	s.mu.Unlock()
+
+	return member
+}
+
+// Close shuts down the server and every cluster member
+func (s *Server) Close() {
+	s.mu.Lock()
+	members := s.members
+	s.mu.Unlock()
+
+	for _, member := range members {
+		member.Close()
+	}
+	s.Server.Close()
+}
+
+// Config returns a client configuration that talks to the fake using DefaultToken
+func (s *Server) Config() *rundeck.Config {
+	return newConfig(s.URL)
+}
+
+// Client returns a client that talks to the fake using DefaultToken
+func (s *Server) Client() *rundeck.Client {
+	return rundeck.NewClient(s.Config())
+}
+
+// Config returns a client configuration that talks to the member using DefaultToken
+func (m *ClusterMember) Config() *rundeck.Config {
+	return newConfig(m.URL)
+}
+
+// Client returns a client that talks to the member using DefaultToken
+func (m *ClusterMember) Client() *rundeck.Client {
+	return rundeck.NewClient(m.Config())
+}
+
+func newConfig(serverURL string) *rundeck.Config {
+	return &rundeck.Config{
+		APIVersion:       rundeck.APIVersion24,
+		RundeckAuthToken: DefaultToken,
+		ServerURL:        serverURL,
+	}
+}
+
+type memberKey struct{}
+
+func (s *Server) memberHandler(uuid string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.serveHTTP(w, r.WithContext(context.WithValue(r.Context(), memberKey{}, uuid)))
+	})
+}
+
+// memberUUID is the server uuid of the cluster member handling the request
+func memberUUID(r *http.Request) string {
+	if uuid, ok := r.Context().Value(memberKey{}).(string); ok {
+		return uuid
+	}
+	return ServerUUID
+}
+
+// executionMode returns the execution mode of the cluster member handling the request
+func (s *Server) executionMode(r *http.Request) rundeck.ExecutionMode {
+	if mode, ok := s.modes[memberUUID(r)]; ok {
+		return mode
+	}
+	return rundeck.ExecutionModeActive
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// handle registers a handler for the pattern, relative to /api/{version}.  Segments wrapped in
+// braces are captured as parameters, and a trailing {path...} captures the rest of the path.
+func (s *Server) handle(method, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, apiPrefix) {
+		writeError(w, http.StatusNotFound, "api.error.invalid.request", "not an api request: "+r.URL.Path)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		writeError(w, http.StatusNotFound, "api.error.invalid.request", "missing api endpoint")
+		return
+	}
+	path := rest[slash+1:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.authToken(r) == nil {
+		writeError(w, http.StatusForbidden, "api.error.item.unauthorized", "invalid or missing auth token")
+		return
+	}
+
+	methodMismatch := false
+	for _, rt := range s.routes {
+		params, ok := matchSegments(rt.segments, path)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodMismatch = true
+			continue
+		}
+		rt.handler(w, r, params)
+		return
+	}
+
+	if methodMismatch {
+		writeError(w, http.StatusMethodNotAllowed, "api.error.invalid.request", r.Method+" is not allowed for "+path)
+		return
+	}
+	writeError(w, http.StatusNotFound, "api.error.invalid.request", "unknown endpoint: "+path)
+}
+
+func matchSegments(pattern []string, path string) (map[string]string, bool) {
+	trailingSlash := strings.HasSuffix(path, "/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	params := make(map[string]string)
+
+	for i, seg := range pattern {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "...}") {
+			rest := strings.Join(parts[i:], "/")
+			if trailingSlash {
+				rest += "/"
+			}
+			params[strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "...}")] = rest
+			return params, true
+		}
+
+		if i >= len(parts) {
+			return nil, false
+		}
+
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params[strings.Trim(seg, "{}")] = parts[i]
+			continue
+		}
+
+		if seg != parts[i] {
+			return nil, false
+		}
+	}
+
+	if len(parts) != len(pattern) {
+		return nil, false
+	}
+	return params, true
+}
+
+func (s *Server) registerRoutes() {
+	s.registerSystemRoutes()
+	s.registerProjectRoutes()
+	s.registerJobRoutes()
+	s.registerExecutionRoutes()
+	s.registerAdhocRoutes()
+	s.registerStorageRoutes()
+	s.registerACLRoutes()
+	s.registerTokenRoutes()
+	s.registerUserRoutes()
+}
+
+// authToken returns the token whose secret is on the request
+func (s *Server) authToken(r *http.Request) *rundeck.Token {
+	secret := r.Header.Get("X-Rundeck-Auth-Token")
+	if secret == "" {
+		return nil
+	}
+	for _, token := range s.tokens {
+		if token.Token == secret {
+			return token
+		}
+	}
+	return nil
+}
+
+// currentUser returns the login that owns the token on the request
+func (s *Server) currentUser(r *http.Request) string {
+	if token := s.authToken(r); token != nil {
+		return token.User
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, rundeck.Error{
+		ErrorPresent: true,
+		APIVersion:   rundeck.APIVersion24,
+		ErrorCode:    code,
+		Message:      message,
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, "api.error.item.doesnotexist", fmt.Sprintf("%s does not exist: %s", kind, id))
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,160 @@
+package rundecktest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+const testJobYAML = `- name: hello
+  group: ops/web
+  description: says hello
+  executionEnabled: true
+  sequence:
+    commands:
+    - exec: echo hello
+`
+
+func TestJobLifecycle(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Test"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	imported, err := cli.Jobs().Import("Test", &rundeck.ImportJobsInput{
+		FileFormat: rundeck.JobFormatYAML,
+		RawContent: []byte(testJobYAML),
+	})
+	if err != nil {
+		t.Fatal("failed to import job", err)
+	}
+	if len(imported.Succeeded) != 1 || imported.Succeeded[0].ID == "" {
+		t.Fatalf("unexpected import response: %+v\n", imported)
+	}
+	id := imported.Succeeded[0].ID
+
+	jobs, err := cli.Jobs().List("Test", &rundeck.ListJobsInput{GroupPath: "ops"})
+	if err != nil {
+		t.Error("failed to list jobs", err)
+	}
+	if len(jobs) != 1 || jobs[0].Name != "hello" || jobs[0].Group != "ops/web" {
+		t.Errorf("unexpected job list: %+v\n", jobs)
+	}
+
+	format := rundeck.JobFormatYAML
+	definition, err := cli.Jobs().GetDefinition(id, &format)
+	if err != nil {
+		t.Error("failed to get definition", err)
+	}
+	if !strings.Contains(string(definition), "uuid: "+id) {
+		t.Errorf("definition should contain the assigned uuid: %s\n", definition)
+	}
+
+	filtered, err := cli.Jobs().Import("Test", &rundeck.ImportJobsInput{
+		FileFormat: rundeck.JobFormatYAML,
+		RawContent: []byte("- name: filtered\n  nodefilters:\n    filter: 'tags: web'\n  sequence:\n    commands:\n    - exec: uptime\n"),
+	})
+	if err != nil {
+		t.Fatal("failed to import job", err)
+	}
+	definition, err = cli.Jobs().GetDefinition(filtered.Succeeded[0].ID, &format)
+	if err != nil {
+		t.Error("failed to get definition", err)
+	}
+	for _, exported := range []string{"threadcount: \"1\"", "excludePrecedence: true", "rankOrder: ascending", "loglevel: INFO", "strategy: node-first"} {
+		if !strings.Contains(string(definition), exported) {
+			t.Errorf("definition should be exported the way Rundeck does, with %s: %s\n", exported, definition)
+		}
+	}
+
+	server.SetExecutionScript(rundecktest.ExecutionScript{
+		Statuses: []rundeck.ExecutionStatus{
+			rundeck.ExecutionStatusRunning,
+			rundeck.ExecutionStatusRunning,
+			rundeck.ExecutionStatusFailed,
+		},
+		Output: []*rundeck.LogEntry{{Log: "hello"}, {Log: "goodbye"}},
+	})
+
+	execution, err := cli.Jobs().Run(id, &rundeck.RunJobInput{Options: map[string]string{"env": "dev"}})
+	if err != nil {
+		t.Fatal("failed to run job", err)
+	}
+	if execution.Status != rundeck.ExecutionStatusRunning || execution.ArgString != "-env dev" {
+		t.Errorf("unexpected execution: %+v\n", execution)
+	}
+
+	for _, expected := range []rundeck.ExecutionStatus{rundeck.ExecutionStatusRunning, rundeck.ExecutionStatusFailed, rundeck.ExecutionStatusFailed} {
+		info, err := cli.Executions().Info(execution.ID)
+		if err != nil {
+			t.Fatal("failed to get execution info", err)
+		}
+		if info.Status != expected {
+			t.Errorf("unexpected execution status.  expected: %s\tactual: %s\n", expected, info.Status)
+		}
+	}
+
+	output, err := cli.Executions().Output(execution.ID, nil)
+	if err != nil {
+		t.Error("failed to get execution output", err)
+	}
+	if !output.ExecCompleted || len(output.Entries) != 2 {
+		t.Errorf("unexpected execution output: %+v\n", output)
+	}
+
+	if err := cli.Jobs().DeleteDefinition(id); err != nil {
+		t.Error("failed to delete job", err)
+	}
+	if _, err := cli.Jobs().GetMetadata(id); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("deleted job should not be found: %v\n", err)
+	}
+}
+
+func TestKeyStorageListing(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+
+	server.PutKey("ssh/web/id_rsa", "application/octet-stream", []byte("private"))
+	server.PutKey("ssh/db.pass", "application/x-rundeck-data-password", []byte("secret"))
+
+	keys, err := cli.KeyStorage().List("ssh")
+	if err != nil {
+		t.Fatal("failed to list keys", err)
+	}
+	if len(keys.Resources) != 2 {
+		t.Fatalf("unexpected number of resources: %d\n", len(keys.Resources))
+	}
+	if keys.Resources[0].Name != "db.pass" || keys.Resources[0].Meta.KeyType != "password" {
+		t.Errorf("unexpected password resource: %+v\n", keys.Resources[0])
+	}
+	if keys.Resources[1].Name != "web" || keys.Resources[1].Type != "directory" {
+		t.Errorf("unexpected directory resource: %+v\n", keys.Resources[1])
+	}
+
+	meta, err := cli.KeyStorage().KeyMetadata("ssh/web", "id_rsa")
+	if err != nil {
+		t.Error("failed to get key metadata", err)
+	}
+	if meta.KeyType != "private" || meta.ContentSize != 7 {
+		t.Errorf("unexpected key metadata: %+v\n", meta)
+	}
+}
+
+func TestInvalidToken(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+
+	config := server.Config()
+	config.RundeckAuthToken = "bogus"
+
+	if _, err := rundeck.NewClient(config).Projects().List(); !errors.Is(err, rundeck.ErrForbidden) {
+		t.Errorf("expected a forbidden error for an invalid token, got: %v\n", err)
+	}
+}
@@ -0,0 +1,214 @@
+package rundecktest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+const (
+	contentTypePassword   = "application/x-rundeck-data-password"
+	contentTypePublicKey  = "application/pgp-keys"
+	contentTypePrivateKey = "application/octet-stream"
+)
+
+type storedKey struct {
+	meta    rundeck.KeyMetadata
+	content []byte
+}
+
+// PutKey stores a key directly, bypassing the API.  The content type determines the key type,
+// and is one of application/x-rundeck-data-password, application/pgp-keys or application/octet-stream.
+func (s *Server) PutKey(keyPath, contentType string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putKey(keyPath, contentType, content)
+}
+
+func (s *Server) putKey(keyPath, contentType string, content []byte) *storedKey {
+	meta := rundeck.KeyMetadata{
+		ContentType: contentType,
+		ContentSize: int64(len(content)),
+	}
+	switch contentType {
+	case contentTypePassword:
+		meta.KeyType = "password"
+		meta.ContentMask = "content"
+	case contentTypePublicKey:
+		meta.KeyType = "public"
+	default:
+		meta.KeyType = "private"
+		meta.ContentMask = "content"
+	}
+
+	key := &storedKey{meta: meta, content: content}
+	s.keys[cleanKeyPath(keyPath)] = key
+	return key
+}
+
+// cleanKeyPath normalizes a key path so it's relative to the keys/ root
+func cleanKeyPath(keyPath string) string {
+	keyPath = strings.Trim(path.Clean("/"+keyPath), "/")
+	if keyPath == "keys" {
+		return ""
+	}
+	return strings.TrimPrefix(keyPath, "keys/")
+}
+
+func (s *Server) registerStorageRoutes() {
+	s.handle(http.MethodGet, "storage/keys", s.getKeys)
+	s.handle(http.MethodGet, "storage/keys/{path...}", s.getKeys)
+	s.handle(http.MethodPost, "storage/keys/{path...}", s.writeKeyContent(true))
+	s.handle(http.MethodPut, "storage/keys/{path...}", s.writeKeyContent(false))
+	s.handle(http.MethodDelete, "storage/keys/{path...}", s.deleteKey)
+}
+
+func (s *Server) keyURL(keyPath string) string {
+	return s.URL + "/api/24/storage/keys/" + keyPath
+}
+
+func (s *Server) keyResource(keyPath string, key *storedKey) *rundeck.KeyResource {
+	res := &rundeck.KeyResource{Name: path.Base(keyPath)}
+	res.Path = "keys/" + keyPath
+	res.URL = s.keyURL(keyPath)
+	if key == nil {
+		res.Type = "directory"
+		return res
+	}
+	res.Type = "file"
+	res.Meta = key.meta
+	return res
+}
+
+func (s *Server) isKeyDirectory(keyPath string) bool {
+	if keyPath == "" {
+		return true
+	}
+	for p := range s.keys {
+		if strings.HasPrefix(p, keyPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getKeys(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	keyPath := cleanKeyPath(params["path"])
+
+	if key, ok := s.keys[keyPath]; ok && !strings.HasSuffix(params["path"], "/") {
+		s.writeKey(w, r, keyPath, key)
+		return
+	}
+
+	if !s.isKeyDirectory(keyPath) {
+		writeNotFound(w, "Resource", "keys/"+keyPath)
+		return
+	}
+
+	prefix := ""
+	if keyPath != "" {
+		prefix = keyPath + "/"
+	}
+
+	children := make(map[string]*storedKey)
+	for p, key := range s.keys {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(p, prefix)
+		if i := strings.Index(rest, "/"); i >= 0 {
+			// a nested key marks an intermediate directory
+			children[prefix+rest[:i]] = nil
+			continue
+		}
+		children[p] = key
+	}
+
+	names := make([]string, 0, len(children))
+	for p := range children {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+
+	list := rundeck.ListKeysResponse{Resources: make([]*rundeck.KeyResource, 0, len(names))}
+	for _, p := range names {
+		list.Resources = append(list.Resources, s.keyResource(p, children[p]))
+	}
+	list.Type = "directory"
+	list.Path = strings.TrimSuffix("keys/"+keyPath, "/")
+	list.URL = s.keyURL(keyPath)
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// writeKey returns metadata, or the content of public keys when it is requested through the Accept header
+func (s *Server) writeKey(w http.ResponseWriter, r *http.Request, keyPath string, key *storedKey) {
+	if r.Header.Get("Accept") == contentTypePublicKey {
+		if key.meta.KeyType != "public" {
+			writeError(w, http.StatusForbidden, "api.error.resource.content.denied", "Cannot read the content of "+keyPath)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypePublicKey)
+		w.Write(key.content)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, key.meta)
+}
+
+// writeKeyContent creates or updates a key, using the request content type as the key's content type
+func (s *Server) writeKeyContent(create bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		keyPath := cleanKeyPath(params["path"])
+		if keyPath == "" || strings.HasSuffix(params["path"], "/") {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", "a key path is required")
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		switch contentType {
+		case contentTypePassword, contentTypePublicKey, contentTypePrivateKey:
+		default:
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", "unsupported content type: "+contentType)
+			return
+		}
+
+		_, exists := s.keys[keyPath]
+		if create && (exists || s.isKeyDirectory(keyPath)) {
+			writeError(w, http.StatusConflict, "api.error.item.alreadyexists", "Resource already exists: keys/"+keyPath)
+			return
+		}
+		if !create && !exists {
+			writeNotFound(w, "Resource", "keys/"+keyPath)
+			return
+		}
+
+		content, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+			return
+		}
+
+		status := http.StatusOK
+		if create {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, s.keyResource(keyPath, s.putKey(keyPath, contentType, content)))
+	}
+}
+
+func (s *Server) deleteKey(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	keyPath := cleanKeyPath(params["path"])
+	if _, ok := s.keys[keyPath]; !ok {
+		writeNotFound(w, "Resource", "keys/"+keyPath)
+		return
+	}
+
+	delete(s.keys, keyPath)
+	w.WriteHeader(http.StatusNoContent)
+}
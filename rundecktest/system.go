@@ -0,0 +1,110 @@
+package rundecktest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+// threadPoolSize is the scheduler thread pool size reported by the fake
+const threadPoolSize = 10
+
+func (s *Server) registerSystemRoutes() {
+	s.handle(http.MethodGet, "system/info", s.systemInfo)
+	s.handle(http.MethodPost, "system/executions/enable", s.setExecutionMode(rundeck.ExecutionModeActive))
+	s.handle(http.MethodPost, "system/executions/disable", s.setExecutionMode(rundeck.ExecutionModePassive))
+	s.handle(http.MethodGet, "system/logstorage", s.logStorage)
+	s.handle(http.MethodGet, "system/logstorage/incomplete", s.incompleteLogStorage)
+	s.handle(http.MethodPost, "system/logstorage/incomplete/resume", s.resumeIncompleteLogStorage)
+}
+
+func (s *Server) systemInfo(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	now := time.Now()
+
+	running := 0
+	for _, exec := range s.executions {
+		if exec.Status == rundeck.ExecutionStatusRunning {
+			running++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, rundeck.SystemInfoResponse{
+		System: rundeck.SystemInfo{
+			Timestamp: rundeck.Timestamp{
+				Epoch:    now.UnixNano() / int64(time.Millisecond),
+				Unit:     "ms",
+				DateTime: now,
+			},
+			Rundeck: rundeck.Rundeck{
+				Version:    "rundecktest",
+				Node:       "localhost",
+				APIVersion: rundeck.APIVersion24,
+				ServerUUID: memberUUID(r),
+			},
+			Executions: rundeck.ExecutionModeResponse{
+				Active:        s.executionMode(r) == rundeck.ExecutionModeActive,
+				ExecutionMode: s.executionMode(r),
+			},
+			Stats: rundeck.Stats{
+				Scheduler: rundeck.SchedulerStats{
+					Running:        running,
+					ThreadPoolSize: threadPoolSize,
+				},
+			},
+		},
+	})
+}
+
+func (s *Server) setExecutionMode(mode rundeck.ExecutionMode) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		s.modes[memberUUID(r)] = mode
+		writeJSON(w, http.StatusOK, rundeck.ExecutionModeResponse{
+			Active:        mode == rundeck.ExecutionModeActive,
+			ExecutionMode: mode,
+		})
+	}
+}
+
+func (s *Server) logStorage(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	writeJSON(w, http.StatusOK, rundeck.LogStorageStats{
+		Enabled:        true,
+		PluginName:     "rundecktest",
+		SucceededCount: int64(len(s.executions)),
+		TotalCount:     int64(len(s.executions)),
+	})
+}
+
+// SetIncompleteLogStorage marks the executions with the given ids as having incomplete log storage
+func (s *Server) SetIncompleteLogStorage(ids ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.incompleteLogs == nil {
+		s.incompleteLogs = make(map[int]bool)
+	}
+	for _, id := range ids {
+		s.incompleteLogs[id] = true
+	}
+}
+
+func (s *Server) incompleteLogStorage(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	execs := s.sortedExecutions(func(e *execution) bool {
+		return s.incompleteLogs[e.ID]
+	})
+
+	page, paging := paginate(len(execs), r.URL.Query())
+	views := make([]*rundeck.Execution, 0, page.end-page.start)
+	for _, e := range execs[page.start:page.end] {
+		views = append(views, e.view(s))
+	}
+
+	writeJSON(w, http.StatusOK, rundeck.IncompleteLogStorageResponse{
+		PagingInfo: paging,
+		Executions: views,
+	})
+}
+
+func (s *Server) resumeIncompleteLogStorage(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	writeJSON(w, http.StatusOK, rundeck.ResumedIncompleteLogStorageResponse{Resumed: true})
+}
@@ -0,0 +1,132 @@
+package rundecktest
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+// defaultTokenDuration is used when a token is created without a duration
+const defaultTokenDuration = 30 * 24 * time.Hour
+
+// SetMaxTokenDuration sets the longest duration tokens can be created with, like Rundeck's
+// rundeck.api.tokens.duration.max setting.  Zero removes the limit.
+func (s *Server) SetMaxTokenDuration(max time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxTokenDuration = max
+}
+
+// PutToken stores a token directly, bypassing the API, e.g. to set up tokens that have already expired.
+// The token authenticates requests only when its Token secret is set.
+func (s *Server) PutToken(token *rundeck.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *token
+	s.tokens[stored.ID] = &stored
+}
+
+func (s *Server) registerTokenRoutes() {
+	s.handle(http.MethodGet, "tokens", s.listTokens)
+	s.handle(http.MethodPost, "tokens", s.createToken)
+	s.handle(http.MethodGet, "tokens/{user}", s.listTokens)
+	s.handle(http.MethodPost, "tokens/{user}", s.createToken)
+	s.handle(http.MethodGet, "token/{id}", s.getToken)
+	s.handle(http.MethodDelete, "token/{id}", s.deleteToken)
+}
+
+func (s *Server) sortedTokens(user string) []*rundeck.Token {
+	tokens := make([]*rundeck.Token, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		if user != "" && token.User != user {
+			continue
+		}
+		view := *token
+		view.Token = ""
+		view.Expired = !view.Expiration.IsZero() && view.Expiration.Before(time.Now())
+		tokens = append(tokens, &view)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID < tokens[j].ID })
+	return tokens
+}
+
+func (s *Server) listTokens(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	writeJSON(w, http.StatusOK, s.sortedTokens(params["user"]))
+}
+
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var input struct {
+		User     string   `json:"user"`
+		Roles    []string `json:"roles"`
+		Duration string   `json:"duration"`
+		Name     string   `json:"name"`
+	}
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	if input.User == "" {
+		input.User = params["user"]
+	}
+	if input.User == "" {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", "user is required")
+		return
+	}
+
+	duration := defaultTokenDuration
+	if input.Duration != "" {
+		d, err := rundeck.ParseTokenDuration(input.Duration)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "api.error.invalid.request", "invalid duration: "+input.Duration)
+			return
+		}
+		duration = d.Duration()
+	}
+	if s.maxTokenDuration > 0 && duration > s.maxTokenDuration {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request",
+			"duration "+input.Duration+" exceeds the maximum of "+rundeck.TokenDuration(s.maxTokenDuration).String())
+		return
+	}
+
+	token := &rundeck.Token{
+		ID:         newUUID(),
+		Token:      newToken(),
+		User:       input.User,
+		Creator:    s.currentUser(r),
+		Roles:      input.Roles,
+		Expiration: time.Now().Add(duration),
+		Name:       input.Name,
+	}
+	s.tokens[token.ID] = token
+	if _, ok := s.users[token.User]; !ok {
+		s.users[token.User] = &rundeck.UserProfile{Login: token.User}
+	}
+
+	view := *token
+	writeJSON(w, http.StatusCreated, &view)
+}
+
+func (s *Server) getToken(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	for _, token := range s.sortedTokens("") {
+		if token.ID == params["id"] {
+			writeJSON(w, http.StatusOK, token)
+			return
+		}
+	}
+	writeNotFound(w, "Token", params["id"])
+}
+
+func (s *Server) deleteToken(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if _, ok := s.tokens[params["id"]]; !ok {
+		writeNotFound(w, "Token", params["id"])
+		return
+	}
+
+	delete(s.tokens, params["id"])
+	w.WriteHeader(http.StatusNoContent)
+}
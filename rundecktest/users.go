@@ -0,0 +1,63 @@
+package rundecktest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/andrewmeissner/go-rundeck"
+)
+
+func (s *Server) registerUserRoutes() {
+	s.handle(http.MethodGet, "user/list", s.listUsers)
+	s.handle(http.MethodGet, "user/info", s.getUser)
+	s.handle(http.MethodGet, "user/info/{login}", s.getUser)
+	s.handle(http.MethodPost, "user/info", s.modifyUser)
+	s.handle(http.MethodPost, "user/info/{login}", s.modifyUser)
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	users := make([]*rundeck.UserProfile, 0, len(s.users))
+	for _, user := range s.users {
+		view := *user
+		users = append(users, &view)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Login < users[j].Login })
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) userFor(r *http.Request, params map[string]string) (*rundeck.UserProfile, string) {
+	login := params["login"]
+	if login == "" {
+		login = s.currentUser(r)
+	}
+	return s.users[login], login
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	user, login := s.userFor(r, params)
+	if user == nil {
+		writeNotFound(w, "User", login)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) modifyUser(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	user, login := s.userFor(r, params)
+	if user == nil {
+		writeNotFound(w, "User", login)
+		return
+	}
+
+	var input rundeck.ModifyUserInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, "api.error.invalid.request", err.Error())
+		return
+	}
+
+	user.FirstName = input.FirstName
+	user.LastName = input.LastName
+	user.Email = input.Email
+	writeJSON(w, http.StatusOK, user)
+}
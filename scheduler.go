@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -69,6 +70,11 @@ func (c *Client) ClusterScheduler() *ClusterScheduler {
 
 // TakeoverSchedule tells the Rundeck server in cluster mode to claim scheduled jobs from another cluster server
 func (cs *ClusterScheduler) TakeoverSchedule(input *TakeoverScheduleInput) (*TakeoverScheduleResponse, error) {
+	return cs.TakeoverScheduleWithContext(context.Background(), input)
+}
+
+// TakeoverScheduleWithContext is the same as TakeoverSchedule with the addition of the ability to pass a context.
+func (cs *ClusterScheduler) TakeoverScheduleWithContext(ctx context.Context, input *TakeoverScheduleInput) (*TakeoverScheduleResponse, error) {
 	if input == nil {
 		return nil, fmt.Errorf("input cannot be nil")
 	}
@@ -79,7 +85,7 @@ func (cs *ClusterScheduler) TakeoverSchedule(input *TakeoverScheduleInput) (*Tak
 		return nil, err
 	}
 
-	res, err := cs.c.checkResponseOK(cs.c.put(url, bytes.NewReader(bs)))
+	res, err := cs.c.checkResponseOK(cs.c.put(ctx, url, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +98,11 @@ func (cs *ClusterScheduler) TakeoverSchedule(input *TakeoverScheduleInput) (*Tak
 // ListScheduledJobs lists scheduled jobs with the schedule owned by the server with the specified uuid.
 // If uuid is nil, then the client server will be used.
 func (cs *ClusterScheduler) ListScheduledJobs(uuid *string) ([]*Job, error) {
+	return cs.ListScheduledJobsWithContext(context.Background(), uuid)
+}
+
+// ListScheduledJobsWithContext is the same as ListScheduledJobs with the addition of the ability to pass a context.
+func (cs *ClusterScheduler) ListScheduledJobsWithContext(ctx context.Context, uuid *string) ([]*Job, error) {
 	url := cs.c.RundeckAddr + "/scheduler"
 
 	if uuid != nil {
@@ -100,7 +111,7 @@ func (cs *ClusterScheduler) ListScheduledJobs(uuid *string) ([]*Job, error) {
 
 	url += "/jobs"
 
-	res, err := cs.c.checkResponseOK(cs.c.get(url))
+	res, err := cs.c.checkResponseOK(cs.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
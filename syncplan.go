@@ -0,0 +1,41 @@
+package rundeck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The job, key and ACL syncs share the shape of their plans: creates, updates and deletes, printed
+// as a summary line followed by one line per change.
+
+// syncActionRank orders the changes of a plan: creates, then updates, then deletes
+func syncActionRank(action string) int {
+	switch action {
+	case "create":
+		return 0
+	case "update":
+		return 1
+	case "delete":
+		return 2
+	}
+	return 3
+}
+
+// syncActionSymbol is the marker printed in front of a change
+func syncActionSymbol(action string) string {
+	switch action {
+	case "create":
+		return "+"
+	case "update":
+		return "~"
+	case "delete":
+		return "-"
+	}
+	return "?"
+}
+
+// writeSyncPlanSummary writes the first line of a printed plan
+func writeSyncPlanSummary(b *strings.Builder, subject string, creates, updates, deletes, unchanged int) {
+	fmt.Fprintf(b, "Plan for %s: %d to create, %d to update, %d to delete, %d unchanged\n",
+		subject, creates, updates, deletes, unchanged)
+}
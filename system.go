@@ -1,6 +1,7 @@
 package rundeck
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -178,9 +179,14 @@ func (c *Client) System() *System {
 
 // Info retrieves Rundeck server information and stats.
 func (s *System) Info() (*SystemInfoResponse, error) {
+	return s.InfoWithContext(context.Background())
+}
+
+// InfoWithContext is the same as Info with the addition of the ability to pass a context.
+func (s *System) InfoWithContext(ctx context.Context) (*SystemInfoResponse, error) {
 	url := s.c.RundeckAddr + "/system/info"
 
-	res, err := s.c.checkResponseOK(s.c.get(url))
+	res, err := s.c.checkResponseOK(s.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +198,11 @@ func (s *System) Info() (*SystemInfoResponse, error) {
 
 // SetExecutionMode sets the execution mode
 func (s *System) SetExecutionMode(mode ExecutionMode) (*ExecutionModeResponse, error) {
+	return s.SetExecutionModeWithContext(context.Background(), mode)
+}
+
+// SetExecutionModeWithContext is the same as SetExecutionMode with the addition of the ability to pass a context.
+func (s *System) SetExecutionModeWithContext(ctx context.Context, mode ExecutionMode) (*ExecutionModeResponse, error) {
 	if mode != ExecutionModeActive && mode != ExecutionModePassive {
 		return nil, fmt.Errorf("received invalid execution mode %s - must be either \"%s\" or \"%s\"", mode, ExecutionModeActive, ExecutionModePassive)
 	}
@@ -205,7 +216,7 @@ func (s *System) SetExecutionMode(mode ExecutionMode) (*ExecutionModeResponse, e
 
 	url += "/" + enabledDisabled
 
-	res, err := s.c.checkResponseOK(s.c.post(url, nil))
+	res, err := s.c.checkResponseOK(s.c.post(ctx, url, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,117 @@
+package rundeck
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var tokenDurationPattern = regexp.MustCompile(`(\d+)([smhdwy])`)
+
+// tokenDurationUnits are the units Rundeck understands, largest first
+var tokenDurationUnits = []struct {
+	suffix string
+	size   time.Duration
+}{
+	{"y", 365 * 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// TokenDuration is the lifetime of an API token.  It converts to and from time.Duration, and is written
+// the way Rundeck understands durations, such as 120d, 2h or 30m.
+type TokenDuration time.Duration
+
+// ParseTokenDuration parses Rundeck's duration syntax: whole numbers followed by one of the units
+// s, m, h, d, w and y, e.g. 120d or 1d12h
+func ParseTokenDuration(value string) (TokenDuration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("invalid token duration: empty")
+	}
+
+	var total time.Duration
+	consumed := 0
+	for _, m := range tokenDurationPattern.FindAllStringSubmatchIndex(value, -1) {
+		if m[0] != consumed {
+			break
+		}
+		n, err := strconv.ParseInt(value[m[2]:m[3]], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid token duration %q: %v", value, err)
+		}
+		for _, unit := range tokenDurationUnits {
+			if unit.suffix != value[m[4]:m[5]] {
+				continue
+			}
+			if n > math.MaxInt64/int64(unit.size) || time.Duration(n)*unit.size > math.MaxInt64-total {
+				return 0, fmt.Errorf("invalid token duration %q: too long", value)
+			}
+			total += time.Duration(n) * unit.size
+		}
+		consumed = m[1]
+	}
+	if consumed != len(value) {
+		return 0, fmt.Errorf("invalid token duration %q: expected numbers followed by s, m, h, d, w or y, e.g. 120d", value)
+	}
+	return TokenDuration(total), nil
+}
+
+// Duration returns the duration as a time.Duration
+func (d TokenDuration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String writes the duration in Rundeck's syntax as a single number of the largest unit that fits
+// exactly, up to days, e.g. 120d or 36h.  Fractions of a second are dropped.
+func (d TokenDuration) String() string {
+	seconds := time.Duration(d).Truncate(time.Second)
+	if seconds <= 0 {
+		return "0s"
+	}
+
+	for _, unit := range tokenDurationUnits[2:] {
+		if seconds%unit.size == 0 {
+			return strconv.FormatInt(int64(seconds/unit.size), 10) + unit.suffix
+		}
+	}
+	return "0s"
+}
+
+// Validate checks the duration can be requested from Rundeck: it must be a positive number of whole
+// seconds, no longer than max when max is known.  A max of zero means there is no known maximum.
+func (d TokenDuration) Validate(max TokenDuration) error {
+	switch {
+	case d <= 0:
+		return fmt.Errorf("token duration must be positive")
+	case time.Duration(d)%time.Second != 0:
+		return fmt.Errorf("token duration %s must be a whole number of seconds", time.Duration(d))
+	case max > 0 && d > max:
+		return fmt.Errorf("token duration %s exceeds the maximum of %s", d, max)
+	}
+	return nil
+}
+
+// MarshalJSON writes the duration in Rundeck's syntax
+func (d TokenDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a duration in Rundeck's syntax
+func (d *TokenDuration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseTokenDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
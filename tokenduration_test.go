@@ -0,0 +1,147 @@
+package rundeck_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestTokenDuration(t *testing.T) {
+	cases := []struct {
+		text     string
+		duration time.Duration
+		format   string
+	}{
+		{"120d", 120 * 24 * time.Hour, "120d"},
+		{"2h", 2 * time.Hour, "2h"},
+		{"30m", 30 * time.Minute, "30m"},
+		{"1d12h", 36 * time.Hour, "36h"},
+		{"2w", 14 * 24 * time.Hour, "14d"},
+		{"1y", 365 * 24 * time.Hour, "365d"},
+		{"90s", 90 * time.Second, "90s"},
+		{"2880h0m0s", 2880 * time.Hour, "120d"},
+	}
+	for _, c := range cases {
+		d, err := rundeck.ParseTokenDuration(c.text)
+		if err != nil {
+			t.Errorf("failed to parse %s: %v\n", c.text, err)
+			continue
+		}
+		if d.Duration() != c.duration {
+			t.Errorf("%s: expected %s, got %s\n", c.text, c.duration, d.Duration())
+		}
+		if d.String() != c.format {
+			t.Errorf("%s: expected to format as %s, got %s\n", c.text, c.format, d)
+		}
+	}
+
+	// the conversion Create's doc comment warns about
+	if d := rundeck.TokenDuration(2880 * time.Hour); d.String() != "120d" {
+		t.Errorf("expected 2880h to format as 120d, got %s\n", d)
+	}
+
+	for _, text := range []string{"", "120", "1.5h", "d", "12x", "-1d", "1d 2h", "999999999999y", "292y292y"} {
+		if _, err := rundeck.ParseTokenDuration(text); err == nil {
+			t.Errorf("expected %q to be rejected\n", text)
+		}
+	}
+
+	var decoded struct {
+		Duration rundeck.TokenDuration `json:"duration"`
+	}
+	if err := json.Unmarshal([]byte(`{"duration":"1d12h"}`), &decoded); err != nil || decoded.Duration.Duration() != 36*time.Hour {
+		t.Errorf("unexpected decoded duration %s: %v\n", decoded.Duration, err)
+	}
+	if bs, err := json.Marshal(decoded); err != nil || string(bs) != `{"duration":"36h"}` {
+		t.Errorf("unexpected encoded duration %s: %v\n", bs, err)
+	}
+
+	max := rundeck.TokenDuration(30 * 24 * time.Hour)
+	if err := rundeck.TokenDuration(30 * 24 * time.Hour).Validate(max); err != nil {
+		t.Error("expected the maximum itself to be valid", err)
+	}
+	for _, d := range []time.Duration{0, -time.Hour, 1500 * time.Millisecond, 31 * 24 * time.Hour} {
+		if err := rundeck.TokenDuration(d).Validate(max); err == nil {
+			t.Errorf("expected %s to be invalid\n", d)
+		}
+	}
+	if err := rundeck.TokenDuration(10 * 365 * 24 * time.Hour).Validate(0); err != nil {
+		t.Error("expected no limit without a maximum", err)
+	}
+}
+
+func TestTokenCreateWithInput(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+
+	config := server.Config()
+	config.APIVersion = rundeck.APIVersion37
+	cli := rundeck.NewClient(config)
+
+	duration := rundeck.TokenDuration(36 * time.Hour)
+	token, err := cli.Tokens().CreateWithInput(&rundeck.CreateTokenInput{
+		User:     "svc-deploy",
+		Roles:    []string{"deploy"},
+		Duration: &duration,
+		Name:     "deploy pipeline",
+	})
+	if err != nil {
+		t.Fatal("failed to create token", err)
+	}
+	if token.Name != "deploy pipeline" || token.User != "svc-deploy" {
+		t.Errorf("unexpected token: %+v\n", token)
+	}
+	if remaining := time.Until(token.Expiration); remaining < 35*time.Hour || remaining > 36*time.Hour {
+		t.Errorf("expected the token to expire in 36h, got %s\n", remaining)
+	}
+
+	fetched, err := cli.Tokens().Get(token.ID)
+	if err != nil {
+		t.Fatal("failed to get token", err)
+	}
+	if fetched.Name != "deploy pipeline" {
+		t.Errorf("expected the name to be kept, got %q\n", fetched.Name)
+	}
+
+	// names need a newer api version than the default
+	if _, err := server.Client().Tokens().CreateWithInput(&rundeck.CreateTokenInput{User: "svc-deploy", Name: "old"}); err == nil {
+		t.Error("expected named tokens to be rejected on api version 24")
+	}
+
+	// a known maximum is checked before anything is sent
+	tooLong := rundeck.TokenDuration(60 * 24 * time.Hour)
+	if _, err := cli.Tokens().CreateWithInput(&rundeck.CreateTokenInput{
+		User:        "svc-deploy",
+		Duration:    &tooLong,
+		MaxDuration: rundeck.TokenDuration(30 * 24 * time.Hour),
+	}); err == nil {
+		t.Error("expected a duration over the maximum to be rejected")
+	}
+
+	// otherwise the server enforces its own
+	server.SetMaxTokenDuration(30 * 24 * time.Hour)
+	_, err = cli.Tokens().CreateWithInput(&rundeck.CreateTokenInput{User: "svc-deploy", Duration: &tooLong})
+	var apiErr *rundeck.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Errorf("expected a 400 from the server, got %v\n", err)
+	}
+
+	// Create sends its free-form duration as written
+	text := "1d12h"
+	created, err := cli.Tokens().Create("svc-deploy", []string{"deploy"}, &text)
+	if err != nil {
+		t.Fatal("failed to create token", err)
+	}
+	if remaining := time.Until(created.Expiration); remaining < 35*time.Hour || remaining > 36*time.Hour {
+		t.Errorf("expected the token to expire in 36h, got %s\n", remaining)
+	}
+	text = "1.5h"
+	_, err = cli.Tokens().Create("svc-deploy", nil, &text)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Errorf("expected a duration Rundeck doesn't understand to be rejected by the server, got %v\n", err)
+	}
+}
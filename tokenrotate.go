@@ -0,0 +1,251 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TokenSink receives a newly created token before the old one is retired, e.g. to write its secret where
+// other processes will pick it up
+type TokenSink interface {
+	Store(ctx context.Context, token *Token) error
+}
+
+// TokenSinkFunc adapts a function to a TokenSink
+type TokenSinkFunc func(ctx context.Context, token *Token) error
+
+// Store calls f
+func (f TokenSinkFunc) Store(ctx context.Context, token *Token) error {
+	return f(ctx, token)
+}
+
+// FileTokenSink writes the token secret to a file readable only by its owner.  The file is replaced in one
+// step, so readers never see a partially written token.
+type FileTokenSink struct {
+	Path string
+}
+
+// Store writes the token
+func (s *FileTokenSink) Store(ctx context.Context, token *Token) error {
+	return writeFileAtomic(s.Path, []byte(token.Token+"\n"))
+}
+
+// EnvTokenSink sets an environment variable to the token secret.  When File is set the variable is written to
+// that env file as NAME=token, replacing any existing line for it, otherwise it is set in this process.
+type EnvTokenSink struct {
+	Name string
+	File string
+}
+
+// Store sets the variable
+func (s *EnvTokenSink) Store(ctx context.Context, token *Token) error {
+	if s.File == "" {
+		return os.Setenv(s.Name, token.Token)
+	}
+
+	content, err := ioutil.ReadFile(s.File)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	line := s.Name + "=" + token.Token
+	var lines []string
+	replaced := false
+	if len(content) > 0 {
+		for _, existing := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+			if !strings.HasPrefix(existing, s.Name+"=") {
+				lines = append(lines, existing)
+			} else if !replaced {
+				lines = append(lines, line)
+				replaced = true
+			}
+		}
+	}
+	if !replaced {
+		lines = append(lines, line)
+	}
+	return writeFileAtomic(s.File, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+func writeFileAtomic(path string, content []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// TokenRotateInput are the parameters for Tokens.Rotate
+type TokenRotateInput struct {
+	// ID is the id of the token to rotate, as listed by the token API.  It is required.
+	ID string
+
+	// SwitchClient makes the client use the new token once the sink has it.  Set it when ID is the token
+	// the client authenticates with.  The switch is safe while other goroutines use the client.
+	SwitchClient bool
+
+	// Sink receives the new token before anything else changes.  It is required.
+	Sink TokenSink
+
+	// Duration is the lifetime of the new token.  If nil, Rundeck will use the configured default.
+	Duration *TokenDuration
+
+	// GracePeriod is how long the old token keeps working after the new one is handed to the sink,
+	// giving everything that uses it time to switch
+	GracePeriod time.Duration
+
+	// KeepOld leaves the old token in place instead of deleting it after the grace period
+	KeepOld bool
+}
+
+// TokenRotation is the outcome of Tokens.Rotate
+type TokenRotation struct {
+	Old *Token
+	New *Token
+	// Switched is set when the client now uses the new token
+	Switched bool
+	// Deleted is set when the old token was deleted
+	Deleted bool
+}
+
+// Rotate replaces a token with a new one for the same user and roles.  The new token is handed to the sink,
+// the client switches to its secret with SetAPIToken when input.SwitchClient is set, and after the grace period
+// the old token is deleted.  When the sink fails the new token is deleted again and the old one is left untouched.
+//
+// Rotate blocks for the grace period.  If ctx is done before it ends, the old token is kept and the rotation
+// is returned along with the context's error.
+func (t *Tokens) Rotate(ctx context.Context, input *TokenRotateInput) (*TokenRotation, error) {
+	if input == nil || input.Sink == nil {
+		return nil, fmt.Errorf("a token sink is required")
+	}
+	if input.ID == "" {
+		return nil, fmt.Errorf("the id of the token to rotate is required")
+	}
+
+	old, err := t.GetWithContext(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := t.CreateWithInputWithContext(ctx, &CreateTokenInput{
+		User:     old.User,
+		Roles:    old.Roles,
+		Duration: input.Duration,
+		Name:     old.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rotation := &TokenRotation{Old: old, New: created}
+
+	if created.Token == "" {
+		err = fmt.Errorf("rundeck did not return the secret of token %s", created.ID)
+	} else {
+		err = input.Sink.Store(ctx, created)
+	}
+	if err != nil {
+		if deleteErr := t.DeleteWithContext(ctx, created.ID); deleteErr != nil {
+			return rotation, fmt.Errorf("failed to store new token: %v (and failed to delete it: %v)", err, deleteErr)
+		}
+		return nil, fmt.Errorf("failed to store new token: %w", err)
+	}
+
+	if input.SwitchClient {
+		t.c.SetAPIToken(created.Token)
+		rotation.Switched = true
+	}
+
+	if input.KeepOld {
+		return rotation, nil
+	}
+
+	if input.GracePeriod > 0 {
+		timer := time.NewTimer(input.GracePeriod)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return rotation, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err := t.DeleteWithContext(ctx, old.ID); err != nil {
+		return rotation, fmt.Errorf("failed to delete old token: %w", err)
+	}
+	rotation.Deleted = true
+	return rotation, nil
+}
+
+// TokenExpiryReport lists the tokens needing attention, soonest to expire first
+type TokenExpiryReport struct {
+	// Expiring are the tokens that expire within the window
+	Expiring []*Token
+	// Expired are the tokens Rundeck reports as expired
+	Expired []*Token
+}
+
+// String renders the report.  Only the last four characters of each token are shown.
+func (r *TokenExpiryReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d token(s) expiring, %d expired\n", len(r.Expiring), len(r.Expired))
+	for _, token := range r.Expiring {
+		fmt.Fprintf(&b, "  expiring %s: %s (user %s)\n", token.Expiration.Format(time.RFC3339), maskToken(token.ID), token.User)
+	}
+	for _, token := range r.Expired {
+		fmt.Fprintf(&b, "  expired %s: %s (user %s)\n", token.Expiration.Format(time.RFC3339), maskToken(token.ID), token.User)
+	}
+	return b.String()
+}
+
+func maskToken(id string) string {
+	if len(id) <= 4 {
+		return strings.Repeat("*", len(id))
+	}
+	return "****" + id[len(id)-4:]
+}
+
+// ScanExpiring reports the tokens that expire within the given window, e.g. 14 * 24 * time.Hour for
+// 14 days, along with those that have already expired.  Tokens without an expiration are never reported.
+func (t *Tokens) ScanExpiring(ctx context.Context, within time.Duration) (*TokenExpiryReport, error) {
+	tokens, err := t.ListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TokenExpiryReport{}
+	deadline := time.Now().Add(within)
+	for _, token := range tokens {
+		switch {
+		case token.Expired:
+			report.Expired = append(report.Expired, token)
+		case !token.Expiration.IsZero() && token.Expiration.Before(deadline):
+			report.Expiring = append(report.Expiring, token)
+		}
+	}
+
+	for _, tokens := range [][]*Token{report.Expiring, report.Expired} {
+		sort.SliceStable(tokens, func(i, j int) bool {
+			return tokens[i].Expiration.Before(tokens[j].Expiration)
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,144 @@
+package rundeck_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestTokenRotate(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	original, err := server.Client().Tokens().Create("svc-deploy", []string{"deploy", "ops"}, nil)
+	if err != nil {
+		t.Fatal("failed to create token", err)
+	}
+	if original.Token == "" || original.Token == original.ID {
+		t.Fatalf("expected the token secret to differ from its id: %+v\n", original)
+	}
+
+	config := server.Config()
+	config.RundeckAuthToken = original.Token
+	cli := rundeck.NewClient(config)
+
+	dir, err := ioutil.TempDir("", "tokenrotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	envFile := filepath.Join(dir, "service.env")
+	if err := ioutil.WriteFile(envFile, []byte("OTHER=1\nRUNDECK_TOKEN="+original.Token+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cli.Tokens().Rotate(ctx, &rundeck.TokenRotateInput{Sink: &rundeck.EnvTokenSink{Name: "RUNDECK_TOKEN"}}); err == nil {
+		t.Error("expected a rotation without a token id to be rejected")
+	}
+
+	rotation, err := cli.Tokens().Rotate(ctx, &rundeck.TokenRotateInput{
+		ID:           original.ID,
+		Sink:         &rundeck.EnvTokenSink{Name: "RUNDECK_TOKEN", File: envFile},
+		GracePeriod:  10 * time.Millisecond,
+		SwitchClient: true,
+	})
+	if err != nil {
+		t.Fatal("failed to rotate token", err)
+	}
+	if !rotation.Switched || !rotation.Deleted || rotation.New.User != "svc-deploy" || strings.Join(rotation.New.Roles, ",") != "deploy,ops" {
+		t.Errorf("unexpected rotation: %+v %+v\n", rotation, rotation.New)
+	}
+
+	content, err := ioutil.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "OTHER=1\nRUNDECK_TOKEN="+rotation.New.Token+"\n" {
+		t.Errorf("unexpected env file:\n%s", content)
+	}
+
+	// the client carries on with the new token, and the old one is gone
+	if _, err := cli.Tokens().Get(rotation.New.ID); err != nil {
+		t.Error("expected the client to use the new token", err)
+	}
+	if _, err := server.Client().Tokens().Get(original.ID); !errors.Is(err, rundeck.ErrNotFound) {
+		t.Errorf("expected the old token to be deleted, received %v\n", err)
+	}
+
+	// a failing sink leaves everything as it was
+	before, _ := server.Client().Tokens().List()
+	failing := rundeck.TokenSinkFunc(func(ctx context.Context, token *rundeck.Token) error {
+		return errors.New("vault is sealed")
+	})
+	if _, err := cli.Tokens().Rotate(ctx, &rundeck.TokenRotateInput{ID: rotation.New.ID, Sink: failing, SwitchClient: true}); err == nil || !strings.Contains(err.Error(), "vault is sealed") {
+		t.Errorf("expected the sink error, received %v\n", err)
+	}
+	after, _ := server.Client().Tokens().List()
+	if len(after) != len(before) || cli.Config.RundeckAuthToken != rotation.New.Token {
+		t.Error("expected a failed rotation to leave the tokens unchanged")
+	}
+
+	// rotating another token writes it to a file and leaves the client alone
+	other, err := server.Client().Tokens().Create("svc-report", []string{"report"}, nil)
+	if err != nil {
+		t.Fatal("failed to create token", err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	rotation, err = server.Client().Tokens().Rotate(canceled, &rundeck.TokenRotateInput{ID: other.ID, Sink: &rundeck.FileTokenSink{Path: tokenFile}, GracePeriod: time.Hour})
+	if err == nil {
+		t.Fatal("expected the canceled context to stop the rotation")
+	}
+	if rotation != nil {
+		t.Errorf("expected nothing to be rotated with a canceled context: %+v\n", rotation)
+	}
+
+	rotation, err = server.Client().Tokens().Rotate(ctx, &rundeck.TokenRotateInput{ID: other.ID, Sink: &rundeck.FileTokenSink{Path: tokenFile}, KeepOld: true})
+	if err != nil {
+		t.Fatal("failed to rotate token", err)
+	}
+	if rotation.Switched || rotation.Deleted {
+		t.Errorf("unexpected rotation: %+v\n", rotation)
+	}
+	content, _ = ioutil.ReadFile(tokenFile)
+	if strings.TrimSpace(string(content)) != rotation.New.Token {
+		t.Errorf("unexpected token file: %s\n", content)
+	}
+}
+
+func TestTokenScanExpiring(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+
+	now := time.Now()
+	server.PutToken(&rundeck.Token{ID: "expired-token-1234", User: "old", Expiration: now.Add(-time.Hour)})
+	server.PutToken(&rundeck.Token{ID: "soon-token-5678", User: "soon", Expiration: now.Add(3 * 24 * time.Hour)})
+	server.PutToken(&rundeck.Token{ID: "sooner-token-9012", User: "sooner", Expiration: now.Add(24 * time.Hour)})
+	server.PutToken(&rundeck.Token{ID: "later-token-3456", User: "later", Expiration: now.Add(90 * 24 * time.Hour)})
+
+	report, err := server.Client().Tokens().ScanExpiring(context.Background(), 7*24*time.Hour)
+	if err != nil {
+		t.Fatal("failed to scan tokens", err)
+	}
+	if len(report.Expired) != 1 || report.Expired[0].User != "old" {
+		t.Errorf("unexpected expired tokens: %v\n", report.Expired)
+	}
+	if len(report.Expiring) != 2 || report.Expiring[0].User != "sooner" || report.Expiring[1].User != "soon" {
+		t.Errorf("unexpected expiring tokens: %v\n", report.Expiring)
+	}
+
+	text := report.String()
+	if !strings.Contains(text, "2 token(s) expiring, 1 expired") || !strings.Contains(text, "****9012 (user sooner)") || strings.Contains(text, "sooner-token") {
+		t.Errorf("unexpected report:\n%s", text)
+	}
+}
@@ -2,18 +2,23 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
-// Token is the information regarding a user token
+// Token is the information regarding a user token.  ID identifies the token in the API, while Token is
+// the secret used to authenticate.  Rundeck only returns the secret when the token is created.
 type Token struct {
 	User       string    `json:"user"`
 	ID         string    `json:"id"`
+	Token      string    `json:"token,omitempty"`
 	Creator    string    `json:"creator"`
 	Expiration time.Time `json:"expiration"`
 	Roles      []string  `json:"roles"`
 	Expired    bool      `json:"expired"`
+	Name       string    `json:"name,omitempty"`
 }
 
 // Tokens is used to perform token specific API operations
@@ -28,9 +33,14 @@ func (c *Client) Tokens() *Tokens {
 
 // List returns all tokens
 func (t *Tokens) List() ([]*Token, error) {
+	return t.ListWithContext(context.Background())
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (t *Tokens) ListWithContext(ctx context.Context) ([]*Token, error) {
 	url := t.c.RundeckAddr + "/tokens"
 
-	res, err := t.c.checkResponseOK(t.c.get(url))
+	res, err := t.c.checkResponseOK(t.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +52,14 @@ func (t *Tokens) List() ([]*Token, error) {
 
 // User returns the tokens associated with the supplied user
 func (t *Tokens) User(user string) ([]*Token, error) {
+	return t.UserWithContext(context.Background(), user)
+}
+
+// UserWithContext is the same as User with the addition of the ability to pass a context.
+func (t *Tokens) UserWithContext(ctx context.Context, user string) ([]*Token, error) {
 	url := t.c.RundeckAddr + "/tokens/" + user
 
-	res, err := t.c.checkResponseOK(t.c.get(url))
+	res, err := t.c.checkResponseOK(t.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -56,9 +71,14 @@ func (t *Tokens) User(user string) ([]*Token, error) {
 
 // Get returns the token by the supplied id
 func (t *Tokens) Get(id string) (*Token, error) {
+	return t.GetWithContext(context.Background(), id)
+}
+
+// GetWithContext is the same as Get with the addition of the ability to pass a context.
+func (t *Tokens) GetWithContext(ctx context.Context, id string) (*Token, error) {
 	url := t.c.RundeckAddr + "/token/" + id
 
-	res, err := t.c.checkResponseOK(t.c.get(url))
+	res, err := t.c.checkResponseOK(t.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +94,63 @@ func (t *Tokens) Get(id string) (*Token, error) {
 // NOTE: the duration needs to be something that rundeck can understand.
 // Unfortunately, this isn't a go parseable duration.  "120d" is understood by Rundeck
 // while "2880h0m0s" is not (what time.Duration.String() returns for the equivalence).
+// The duration is sent as written.  CreateWithInput takes a TokenDuration instead, which converts
+// from a time.Duration and is checked before the token is requested.
 func (t *Tokens) Create(user string, roles []string, duration *string) (*Token, error) {
+	return t.CreateWithContext(context.Background(), user, roles, duration)
+}
+
+// CreateWithContext is the same as Create with the addition of the ability to pass a context.
+func (t *Tokens) CreateWithContext(ctx context.Context, user string, roles []string, duration *string) (*Token, error) {
+	return t.create(ctx, user, roles, duration, "")
+}
+
+// CreateTokenInput are the parameters for Tokens.CreateWithInput
+type CreateTokenInput struct {
+	User  string
+	Roles []string
+
+	// Duration is the lifetime of the token.  If nil, Rundeck will use the configured default.
+	Duration *TokenDuration
+
+	// Name is a label for the token.  Named tokens need APIVersion37 or later.
+	Name string
+
+	// MaxDuration is the longest duration the server allows, its rundeck.api.tokens.duration.max setting.
+	// The API does not publish that setting, so it is only known when the caller sets it here.  When set,
+	// longer durations are rejected before the token is requested; otherwise Rundeck rejects them itself
+	// and the error is an *APIError.
+	MaxDuration TokenDuration
+}
+
+// CreateWithInput creates a token based on the supplied input
+func (t *Tokens) CreateWithInput(input *CreateTokenInput) (*Token, error) {
+	return t.CreateWithInputWithContext(context.Background(), input)
+}
+
+// CreateWithInputWithContext is the same as CreateWithInput with the addition of the ability to pass a context.
+func (t *Tokens) CreateWithInputWithContext(ctx context.Context, input *CreateTokenInput) (*Token, error) {
+	if input == nil || input.User == "" {
+		return nil, fmt.Errorf("a user is required")
+	}
+	if input.Name != "" && t.c.Config.APIVersion < APIVersion37 {
+		return nil, fmt.Errorf("named tokens need api version %d or later, the client uses %d", APIVersion37, t.c.Config.APIVersion)
+	}
+
+	var duration *string
+	if input.Duration != nil {
+		if err := input.Duration.Validate(input.MaxDuration); err != nil {
+			return nil, err
+		}
+		text := input.Duration.String()
+		duration = &text
+	}
+
+	return t.create(ctx, input.User, input.Roles, duration, input.Name)
+}
+
+// create requests a token, sending the duration as it is given
+func (t *Tokens) create(ctx context.Context, user string, roles []string, duration *string, name string) (*Token, error) {
 	url := t.c.RundeckAddr + "/tokens"
 
 	payload := map[string]interface{}{
@@ -86,12 +162,16 @@ func (t *Tokens) Create(user string, roles []string, duration *string) (*Token,
 		payload["duration"] = stringValue(duration)
 	}
 
+	if name != "" {
+		payload["name"] = name
+	}
+
 	bs, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := t.c.checkResponseCreated(t.c.post(url, bytes.NewReader(bs)))
+	res, err := t.c.checkResponseCreated(t.c.post(ctx, url, bytes.NewReader(bs)))
 	if err != nil {
 		return nil, err
 	}
@@ -103,9 +183,14 @@ func (t *Tokens) Create(user string, roles []string, duration *string) (*Token,
 
 // Delete deletes a token
 func (t *Tokens) Delete(id string) error {
+	return t.DeleteWithContext(context.Background(), id)
+}
+
+// DeleteWithContext is the same as Delete with the addition of the ability to pass a context.
+func (t *Tokens) DeleteWithContext(ctx context.Context, id string) error {
 	url := t.c.RundeckAddr + "/token/" + id
 
-	res, err := t.c.checkResponseNoContent(t.c.delete(url, nil))
+	res, err := t.c.checkResponseNoContent(t.c.delete(ctx, url, nil))
 	if err != nil {
 		return err
 	}
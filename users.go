@@ -2,6 +2,7 @@ package rundeck
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,9 +35,14 @@ func (c *Client) Users() *Users {
 
 // List returns a list of all the users
 func (u *Users) List() ([]*UserProfile, error) {
+	return u.ListWithContext(context.Background())
+}
+
+// ListWithContext is the same as List with the addition of the ability to pass a context.
+func (u *Users) ListWithContext(ctx context.Context) ([]*UserProfile, error) {
 	url := u.c.RundeckAddr + "/user/list"
 
-	res, err := u.c.checkResponseOK(u.c.get(url))
+	res, err := u.c.checkResponseOK(u.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -51,13 +57,18 @@ func (u *Users) List() ([]*UserProfile, error) {
 // If the login parameter is nil, the profile associated with
 // the supplied auth token will be returned.
 func (u *Users) Get(login *string) (*UserProfile, error) {
+	return u.GetWithContext(context.Background(), login)
+}
+
+// GetWithContext is the same as Get with the addition of the ability to pass a context.
+func (u *Users) GetWithContext(ctx context.Context, login *string) (*UserProfile, error) {
 	url := u.c.RundeckAddr + "/user/info"
 
 	if login != nil {
 		url += "/" + stringValue(login)
 	}
 
-	res, err := u.c.checkResponseOK(u.c.get(url))
+	res, err := u.c.checkResponseOK(u.c.get(ctx, url))
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +83,11 @@ func (u *Users) Get(login *string) (*UserProfile, error) {
 // If the user parameter is nil, then the user associated with
 // the auth token will be modified.
 func (u *Users) Modify(login *string, input *ModifyUserInput) (*UserProfile, error) {
+	return u.ModifyWithContext(context.Background(), login, input)
+}
+
+// ModifyWithContext is the same as Modify with the addition of the ability to pass a context.
+func (u *Users) ModifyWithContext(ctx context.Context, login *string, input *ModifyUserInput) (*UserProfile, error) {
 	if input == nil {
 		return nil, fmt.Errorf("the parameter ModifyUserInput cannot be nil")
 	}
@@ -91,7 +107,7 @@ func (u *Users) Modify(login *string, input *ModifyUserInput) (*UserProfile, err
 		body = bytes.NewReader(bs)
 	}
 
-	res, err := u.c.checkResponseOK(u.c.post(url, body))
+	res, err := u.c.checkResponseOK(u.c.post(ctx, url, body))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,163 @@
+package rundeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWaitInterval    = 2 * time.Second
+	defaultWaitMaxInterval = 30 * time.Second
+	defaultWaitMultiplier  = 1.5
+)
+
+var (
+	// ErrWaitTimeout is returned by Executions.Wait when the execution is still running once the
+	// WaitInput.Timeout or the context deadline is reached
+	ErrWaitTimeout = errors.New("rundeck: timed out waiting for execution")
+
+	// ErrExecutionFailed is matched by an ExecutionError for executions that failed
+	ErrExecutionFailed = errors.New("rundeck: execution failed")
+
+	// ErrExecutionAborted is matched by an ExecutionError for executions that were aborted
+	ErrExecutionAborted = errors.New("rundeck: execution aborted")
+
+	// ErrExecutionTimedOut is matched by an ExecutionError for executions that exceeded the job timeout
+	ErrExecutionTimedOut = errors.New("rundeck: execution timed out")
+)
+
+// WaitInput configures how Executions.Wait polls for completion
+type WaitInput struct {
+	// Interval is the delay between the first poll, made straight away, and the second.  Defaults to 2s.
+	Interval time.Duration
+
+	// MaxInterval caps the delay between polls.  Defaults to 30s.
+	MaxInterval time.Duration
+
+	// Multiplier grows the delay after every poll.  Defaults to 1.5, use 1 for a fixed interval.
+	Multiplier float64
+
+	// Timeout bounds the total time spent waiting.  If zero, only the context bounds it.
+	Timeout time.Duration
+
+	// Progress is called with the execution after every poll
+	Progress func(*Execution)
+}
+
+// ExecutionError is returned by Executions.Wait when an execution finishes unsuccessfully.
+//
+// Use errors.Is with ErrExecutionFailed, ErrExecutionAborted or ErrExecutionTimedOut to branch on the outcome.
+type ExecutionError struct {
+	Execution *Execution
+}
+
+// Error describes the execution and its final status
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("rundeck: execution %d finished with status %s", e.Execution.ID, e.Execution.Status)
+}
+
+// Is allows matching an ExecutionError against the sentinel errors by status
+func (e *ExecutionError) Is(target error) bool {
+	switch e.Execution.Status {
+	case ExecutionStatusAborted:
+		return target == ErrExecutionAborted
+	case ExecutionStatusTimedout:
+		return target == ErrExecutionTimedOut
+	case ExecutionStatusFailed, ExecutionStatusFailedWithRetry:
+		return target == ErrExecutionFailed
+	}
+	return false
+}
+
+type waitTimeoutError struct {
+	id    int
+	cause error
+}
+
+func (e *waitTimeoutError) Error() string {
+	return fmt.Sprintf("%s %d: %v", ErrWaitTimeout, e.id, e.cause)
+}
+
+func (e *waitTimeoutError) Is(target error) bool {
+	return target == ErrWaitTimeout
+}
+
+func (e *waitTimeoutError) Unwrap() error {
+	return e.cause
+}
+
+// Wait polls the execution until its status is no longer running or scheduled, and returns the final execution.
+//
+// Failed, aborted and timed out executions are returned along with an *ExecutionError.  If the wait
+// itself times out, the last polled execution is returned with an error matching ErrWaitTimeout.
+func (e *Executions) Wait(ctx context.Context, id int, input *WaitInput) (*Execution, error) {
+	if input == nil {
+		input = &WaitInput{}
+	}
+
+	interval := input.Interval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	maxInterval := input.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWaitMaxInterval
+	}
+	multiplier := input.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultWaitMultiplier
+	}
+
+	if input.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, input.Timeout)
+		defer cancel()
+	}
+
+	// poll straight away, so that an execution which has already finished costs no interval
+	var execution *Execution
+	for {
+		current, err := e.InfoWithContext(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return execution, waitError(ctx, id)
+			}
+			return execution, err
+		}
+		execution = current
+
+		if input.Progress != nil {
+			input.Progress(execution)
+		}
+
+		switch execution.Status {
+		case ExecutionStatusRunning, ExecutionStatusScheduled:
+		case ExecutionStatusFailed, ExecutionStatusFailedWithRetry, ExecutionStatusAborted, ExecutionStatusTimedout:
+			return execution, &ExecutionError{Execution: execution}
+		default:
+			return execution, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return execution, waitError(ctx, id)
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * multiplier)
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+func waitError(ctx context.Context, id int) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return &waitTimeoutError{id: id, cause: ctx.Err()}
+	}
+	return ctx.Err()
+}
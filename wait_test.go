@@ -0,0 +1,94 @@
+package rundeck_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andrewmeissner/go-rundeck"
+	"github.com/andrewmeissner/go-rundeck/rundecktest"
+)
+
+func TestWaitForExecution(t *testing.T) {
+	server := rundecktest.NewServer()
+	defer server.Close()
+	cli := server.Client()
+
+	if _, err := cli.Projects().Create(&rundeck.CreateProjectInput{Name: "Test"}); err != nil {
+		t.Fatal("failed to create project", err)
+	}
+
+	run := func(final rundeck.ExecutionStatus) int {
+		server.SetExecutionScript(rundecktest.ExecutionScript{
+			Statuses: []rundeck.ExecutionStatus{rundeck.ExecutionStatusRunning, rundeck.ExecutionStatusRunning, final},
+		})
+		resp, err := cli.Adhoc().RunCommandString(&rundeck.AdhocCommandStringInput{
+			Exec:         "pwd",
+			AdhocOptions: rundeck.AdhocOptions{Project: "Test"},
+		})
+		if err != nil {
+			t.Fatal("failed to run adhoc command", err)
+		}
+		return resp.Execution.ID
+	}
+
+	input := &rundeck.WaitInput{Interval: time.Millisecond, Multiplier: 1}
+
+	polls := 0
+	input.Progress = func(*rundeck.Execution) { polls++ }
+	execution, err := cli.Executions().Wait(context.Background(), run(rundeck.ExecutionStatusSucceeded), input)
+	if err != nil {
+		t.Error("successful execution should not return an error", err)
+	}
+	if execution.Status != rundeck.ExecutionStatusSucceeded || polls != 2 {
+		t.Errorf("unexpected result.  status: %s\tpolls: %d\n", execution.Status, polls)
+	}
+	input.Progress = nil
+
+	_, err = cli.Executions().Wait(context.Background(), run(rundeck.ExecutionStatusFailed), input)
+	if !errors.Is(err, rundeck.ErrExecutionFailed) {
+		t.Errorf("expected ErrExecutionFailed, got: %v\n", err)
+	}
+
+	_, err = cli.Executions().Wait(context.Background(), run(rundeck.ExecutionStatusAborted), input)
+	if !errors.Is(err, rundeck.ErrExecutionAborted) || errors.Is(err, rundeck.ErrExecutionFailed) {
+		t.Errorf("expected only ErrExecutionAborted, got: %v\n", err)
+	}
+
+	server.SetExecutionScript(rundecktest.ExecutionScript{
+		Statuses: []rundeck.ExecutionStatus{rundeck.ExecutionStatusRunning},
+	})
+	resp, err := cli.Adhoc().RunCommandString(&rundeck.AdhocCommandStringInput{
+		Exec:         "sleep 600",
+		AdhocOptions: rundeck.AdhocOptions{Project: "Test"},
+	})
+	if err != nil {
+		t.Fatal("failed to run adhoc command", err)
+	}
+
+	input.Timeout = 20 * time.Millisecond
+	execution, err = cli.Executions().Wait(context.Background(), resp.Execution.ID, input)
+	if !errors.Is(err, rundeck.ErrWaitTimeout) {
+		t.Errorf("expected ErrWaitTimeout, got: %v\n", err)
+	}
+	if execution == nil || execution.Status != rundeck.ExecutionStatusRunning {
+		t.Errorf("the last polled execution should be returned on timeout: %+v\n", execution)
+	}
+
+	// a finished execution is returned from the first poll, without waiting an interval
+	server.SetExecutionScript(rundecktest.ExecutionScript{
+		Statuses: []rundeck.ExecutionStatus{rundeck.ExecutionStatusSucceeded},
+	})
+	resp, err = cli.Adhoc().RunCommandString(&rundeck.AdhocCommandStringInput{
+		Exec:         "true",
+		AdhocOptions: rundeck.AdhocOptions{Project: "Test"},
+	})
+	if err != nil {
+		t.Fatal("failed to run adhoc command", err)
+	}
+	execution, err = cli.Executions().Wait(context.Background(), resp.Execution.ID, &rundeck.WaitInput{Interval: time.Hour, Timeout: time.Second})
+	if err != nil || execution.Status != rundeck.ExecutionStatusSucceeded {
+		t.Errorf("expected the finished execution straight away, got %+v: %v\n", execution, err)
+	}
+}